@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,23 +11,85 @@ import (
 
 	"github.com/ovestokke/gemcheck-tui/internal/app"
 	"github.com/ovestokke/gemcheck-tui/internal/cache"
+	"github.com/ovestokke/gemcheck-tui/internal/cli"
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/history"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+	"github.com/ovestokke/gemcheck-tui/internal/state"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
 )
 
 func main() {
-	home, err := os.UserHomeDir()
+	fs := flag.NewFlagSet("gemcheck", flag.ContinueOnError)
+	flags := config.NewFlags(fs)
+	league := fs.String("league", "", "league ID to open in the TUI, skipping league selection; need not be listed, e.g. a private league")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), cli.Usage)
+		fmt.Fprintln(fs.Output(), "\nGlobal flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+
+	var cfgPath string
+	load := func() (config.Config, error) {
+		cfg, path, err := config.Resolve(flags, os.Getenv)
+		cfgPath = path
+		return cfg, err
+	}
+	cfg, err := load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	cacheDir := filepath.Join(home, ".cache", "gemcheck")
-	c := cache.New(cacheDir)
+	c := cache.New(cfg.CacheDir)
+	l := loader.New(c, history.New(filepath.Join(cfg.CacheDir, "history")))
+	l.SetTTL(cfg.TTL.Loader())
 
-	m := app.NewModel(c)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	if args := fs.Args(); len(args) > 0 {
+		if err := cli.Run(l, cfg, args, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := tui.ApplyKeys(cfg.Keys); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid config: %v\n", err)
+		os.Exit(1)
+	}
+	m := app.NewModel(l, cfg, load)
+	m.SetConfigPath(cfgPath)
+	if dir, err := config.Dir(); err == nil {
+		m.SetWatchlistDir(filepath.Join(dir, "watchlist"))
+	}
+
+	// A missing or unreadable state file just means starting fresh
+	statePath, err := state.Path()
+	var saved state.State
+	if err == nil {
+		saved, _ = state.Load(statePath)
+	}
+	m.Restore(saved)
+	if *league != "" {
+		m.OpenLeague(*league)
+	}
 
-	if _, err := p.Run(); err != nil {
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	// Key handling leaves either a Model or a *Model behind
+	if fm, ok := final.(interface{ State() state.State }); ok && statePath != "" {
+		if err := state.Save(statePath, fm.State()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save UI state: %v\n", err)
+		}
+	}
 }
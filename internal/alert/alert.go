@@ -0,0 +1,272 @@
+// Package alert evaluates user-defined rules against successive
+// domain.ProcessedResults, as produced by `gemcheck watch`.
+//
+// Rules are edge-triggered: a pool EV threshold fires when it is crossed, a
+// price move fires once the move since the last alert (or since watching
+// began) exceeds the limit, and a bingo rule fires when a gem newly enters a
+// pool's top list.
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/duration"
+)
+
+// Kind is the type of condition a rule checks.
+type Kind string
+
+const (
+	PoolEV    Kind = "pool_ev"    // a color's pool EV crosses Above or Below
+	PriceMove Kind = "price_move" // a gem's price moves more than Percent
+	BingoNew  Kind = "bingo_new"  // a gem enters a color's bingo list
+)
+
+// Rule is one alert rule as written in the config file.
+type Rule struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+
+	// Color limits the rule to one pool (r, g or b); empty checks all three.
+	Color string `json:"color,omitempty"`
+
+	// PoolEV thresholds; set exactly one.
+	Above *float64 `json:"above,omitempty"`
+	Below *float64 `json:"below,omitempty"`
+
+	// PriceMove: a transfigured gem name, or empty for every gem priced at
+	// least MinPrice.
+	Gem      string  `json:"gem,omitempty"`
+	Percent  float64 `json:"percent,omitempty"`
+	MinPrice float64 `json:"min_price,omitempty"`
+
+	// Cooldown is the minimum time between webhook notifications for the
+	// rule, e.g. "30m".
+	Cooldown duration.Duration `json:"cooldown,omitempty"`
+}
+
+// Validate reports the first problem with the rule's fields.
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("alert rule needs a name")
+	}
+	if r.Color != "" {
+		if _, err := domain.ParseColor(r.Color); err != nil {
+			return fmt.Errorf("alert %q: %w", r.Name, err)
+		}
+	}
+	switch r.Kind {
+	case PoolEV:
+		if (r.Above == nil) == (r.Below == nil) {
+			return fmt.Errorf("alert %q: pool_ev needs exactly one of above or below", r.Name)
+		}
+	case PriceMove:
+		if r.Percent <= 0 {
+			return fmt.Errorf("alert %q: price_move needs a positive percent", r.Name)
+		}
+	case BingoNew:
+	default:
+		return fmt.Errorf("alert %q: unknown kind %q (want pool_ev, price_move or bingo_new)", r.Name, r.Kind)
+	}
+	return nil
+}
+
+func (r Rule) colors() []domain.GemColor {
+	if c, err := domain.ParseColor(r.Color); err == nil {
+		return []domain.GemColor{c}
+	}
+	return domain.AllColors
+}
+
+// Alert is a fired rule.
+type Alert struct {
+	Rule     string
+	Kind     Kind
+	Time     time.Time
+	Color    domain.GemColor // empty for rules not tied to a pool
+	Gem      string          // empty for pool EV alerts
+	Value    float64         // current pool EV or price
+	Previous float64         // the threshold, or the price moved from
+	Message  string
+}
+
+// ruleState is what a rule remembers between evaluations.
+type ruleState struct {
+	rule   Rule
+	primed bool
+	met    map[domain.GemColor]bool // PoolEV: threshold met last time
+	ref    map[string]float64       // PriceMove: price at the last alert
+	bingo  map[string]bool          // BingoNew: bingo gems last time
+}
+
+// Evaluator checks rules against each new result.
+type Evaluator struct {
+	states []*ruleState
+}
+
+// NewEvaluator validates rules and returns an evaluator for them.
+func NewEvaluator(rules []Rule) (*Evaluator, error) {
+	ev := &Evaluator{}
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		ev.states = append(ev.states, &ruleState{
+			rule:  r,
+			met:   make(map[domain.GemColor]bool),
+			ref:   make(map[string]float64),
+			bingo: make(map[string]bool),
+		})
+	}
+	return ev, nil
+}
+
+// Evaluate checks every rule against result and returns the alerts that
+// fired, in rule order. The first call establishes baselines: price and
+// bingo rules only fire from the second call, while a pool EV threshold that
+// is already met fires straight away.
+func (ev *Evaluator) Evaluate(result domain.ProcessedResult, at time.Time) []Alert {
+	prices := variantPrices(result)
+	var alerts []Alert
+	for _, s := range ev.states {
+		switch s.rule.Kind {
+		case PoolEV:
+			alerts = append(alerts, s.poolEV(result, at)...)
+		case PriceMove:
+			alerts = append(alerts, s.priceMove(prices, at)...)
+		case BingoNew:
+			alerts = append(alerts, s.bingoNew(result, at)...)
+		}
+		s.primed = true
+	}
+	return alerts
+}
+
+// Prime sets the baselines from an earlier result, such as the last fetch
+// in price history, without firing anything. The next Evaluate then
+// compares against it, so price and bingo rules can fire on a single check.
+func (ev *Evaluator) Prime(result domain.ProcessedResult, at time.Time) {
+	ev.Evaluate(result, at)
+}
+
+func (s *ruleState) poolEV(result domain.ProcessedResult, at time.Time) []Alert {
+	var alerts []Alert
+	for _, c := range s.rule.colors() {
+		ev := result.ColorStats[c].PoolEV
+		var met bool
+		var threshold float64
+		var verb string
+		if s.rule.Above != nil {
+			threshold, met, verb = *s.rule.Above, ev > *s.rule.Above, "rose above"
+		} else {
+			threshold, met, verb = *s.rule.Below, ev < *s.rule.Below, "fell below"
+		}
+		if met && !s.met[c] {
+			alerts = append(alerts, Alert{
+				Rule: s.rule.Name, Kind: PoolEV, Time: at, Color: c,
+				Value: ev, Previous: threshold,
+				Message: fmt.Sprintf("%s pool EV %s %s %s",
+					c.Label(), domain.FormatChaos(ev), verb, domain.FormatChaos(threshold)),
+			})
+		}
+		s.met[c] = met
+	}
+	return alerts
+}
+
+func (s *ruleState) priceMove(prices map[string]variantPrice, at time.Time) []Alert {
+	colors := s.rule.colors()
+	var alerts []Alert
+	for _, name := range sortedKeys(prices) {
+		p := prices[name]
+		if s.rule.Gem != "" && !strings.EqualFold(name, s.rule.Gem) {
+			continue
+		}
+		if s.rule.Gem == "" && !hasColor(colors, p.color) {
+			continue
+		}
+		ref, ok := s.ref[name]
+		if !ok || ref <= 0 {
+			s.ref[name] = p.price
+			continue
+		}
+		change := (p.price - ref) / ref * 100
+		if math.Abs(change) <= s.rule.Percent {
+			continue
+		}
+		s.ref[name] = p.price
+		if s.rule.Gem == "" && math.Max(p.price, ref) < s.rule.MinPrice {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Rule: s.rule.Name, Kind: PriceMove, Time: at, Color: p.color, Gem: name,
+			Value: p.price, Previous: ref,
+			Message: fmt.Sprintf("%s %s → %s (%+.1f%%)",
+				name, domain.FormatChaos(ref), domain.FormatChaos(p.price), change),
+		})
+	}
+	return alerts
+}
+
+func (s *ruleState) bingoNew(result domain.ProcessedResult, at time.Time) []Alert {
+	var alerts []Alert
+	current := make(map[string]bool)
+	for _, c := range s.rule.colors() {
+		for _, b := range result.ColorStats[c].Bingo {
+			current[b.Name] = true
+			if !s.primed || s.bingo[b.Name] {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Rule: s.rule.Name, Kind: BingoNew, Time: at, Color: c, Gem: b.Name,
+				Value: b.SellPrice,
+				Message: fmt.Sprintf("%s entered the %s bingo list at %s (%s per roll)",
+					b.Name, c.Label(), domain.FormatChaos(b.SellPrice), domain.FormatPct(b.Prob)),
+			})
+		}
+	}
+	s.bingo = current
+	return alerts
+}
+
+type variantPrice struct {
+	price float64
+	color domain.GemColor
+}
+
+// variantPrices collects the listed price of every transfigured gem.
+func variantPrices(result domain.ProcessedResult) map[string]variantPrice {
+	prices := make(map[string]variantPrice)
+	for _, e := range result.GemPicks {
+		for _, v := range e.Variants {
+			if v.Listed {
+				prices[v.Name] = variantPrice{price: v.SellPrice, color: e.Color}
+			}
+		}
+	}
+	return prices
+}
+
+func sortedKeys(m map[string]variantPrice) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasColor(colors []domain.GemColor, c domain.GemColor) bool {
+	for _, x := range colors {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
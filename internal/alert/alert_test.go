@@ -0,0 +1,114 @@
+package alert
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+var testWiki = domain.WikiData{TransfigGems: map[domain.GemColor][]string{
+	domain.Red:  {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+	domain.Blue: {"Arc of Surging", "Arc of Oscillating"},
+}}
+
+func result(carnage, trauma, surging float64) domain.ProcessedResult {
+	prices := []domain.GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: carnage},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: trauma},
+	}
+	if surging > 0 {
+		prices = append(prices, domain.GemPrice{Name: "Arc of Surging", ChaosValue: surging})
+	}
+	return domain.ProcessGems(testWiki, prices, 1, nil)
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func TestEvaluate(t *testing.T) {
+	ev, err := NewEvaluator([]Rule{
+		{Name: "red up", Kind: PoolEV, Color: "r", Above: ptr(90)},
+		{Name: "carnage", Kind: PriceMove, Gem: "boneshatter of carnage", Percent: 20},
+		{Name: "cheap movers", Kind: PriceMove, Percent: 20, MinPrice: 70},
+		{Name: "blue bingo", Kind: BingoNew, Color: "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []struct {
+		result domain.ProcessedResult
+		want   []string // rule names that fire
+	}{
+		// Baseline: red pool EV 93.75 already above 90
+		{result(100, 50, 0), []string{"red up"}},
+		// Carnage +10%: nothing; red still above, no re-fire
+		{result(110, 50, 0), nil},
+		// Carnage +30% from baseline; Trauma +30% but under min_price; Surging new in bingo
+		{result(130, 65, 200), []string{"carnage", "cheap movers", "blue bingo"}},
+		// Red drops below, then comes back up
+		{result(20, 20, 200), []string{"carnage", "cheap movers"}},
+		{result(20, 110, 200), []string{"red up", "cheap movers"}},
+	}
+	for i, step := range steps {
+		alerts := ev.Evaluate(step.result, at.Add(time.Duration(i)*time.Minute))
+		var got []string
+		for _, a := range alerts {
+			got = append(got, a.Rule)
+		}
+		if len(got) != len(step.want) {
+			t.Errorf("step %d: fired %v, want %v", i, got, step.want)
+			continue
+		}
+		for j := range got {
+			if got[j] != step.want[j] {
+				t.Errorf("step %d: fired %v, want %v", i, got, step.want)
+				break
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []Rule{
+		{Kind: PoolEV, Above: ptr(1)},
+		{Name: "x", Kind: PoolEV},
+		{Name: "x", Kind: PoolEV, Above: ptr(1), Below: ptr(2)},
+		{Name: "x", Kind: PriceMove},
+		{Name: "x", Kind: BingoNew, Color: "purple"},
+		{Name: "x", Kind: "volume"},
+	}
+	for _, r := range bad {
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected an error for %+v", r)
+		}
+	}
+	if _, err := NewEvaluator(bad[:1]); err == nil {
+		t.Error("NewEvaluator accepted an invalid rule")
+	}
+}
+
+func TestPrime(t *testing.T) {
+	ev, err := NewEvaluator([]Rule{
+		{Name: "red up", Kind: PoolEV, Color: "r", Above: ptr(90)},
+		{Name: "carnage", Kind: PriceMove, Gem: "boneshatter of carnage", Percent: 20},
+		{Name: "blue bingo", Kind: BingoNew, Color: "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Primed with an earlier fetch, one check fires price and bingo rules,
+	// and a pool EV threshold already met before doesn't fire again
+	ev.Prime(result(100, 50, 0), at)
+	var got []string
+	for _, a := range ev.Evaluate(result(130, 50, 200), at.Add(time.Hour)) {
+		got = append(got, a.Rule)
+	}
+	if want := []string{"carnage", "blue bingo"}; !slices.Equal(got, want) {
+		t.Errorf("fired %v, want %v", got, want)
+	}
+}
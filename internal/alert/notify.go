@@ -0,0 +1,319 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// Webhook payload formats.
+const (
+	FormatGeneric = "generic"
+	FormatDiscord = "discord"
+)
+
+// discordMaxEmbeds is the most embeds Discord accepts in one message.
+const discordMaxEmbeds = 10
+
+// Webhook is a URL alerts are POSTed to.
+type Webhook struct {
+	URL string `json:"url"`
+
+	// Format is "discord" or "generic". Empty picks discord for discord.com
+	// webhook URLs and generic otherwise.
+	Format string `json:"format,omitempty"`
+
+	// Rules limits the webhook to these rule names; empty sends every alert.
+	Rules []string `json:"rules,omitempty"`
+}
+
+// Validate reports a malformed URL or unknown format.
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook %q: not an http(s) URL", w.URL)
+	}
+	switch w.Format {
+	case "", FormatGeneric, FormatDiscord:
+		return nil
+	}
+	return fmt.Errorf("webhook %q: unknown format %q (want discord or generic)", w.URL, w.Format)
+}
+
+func (w Webhook) format() string {
+	if w.Format == "" && strings.Contains(w.URL, "discord.com/api/webhooks/") {
+		return FormatDiscord
+	}
+	if w.Format == "" {
+		return FormatGeneric
+	}
+	return w.Format
+}
+
+func (w Webhook) wants(rule string) bool {
+	if len(w.Rules) == 0 {
+		return true
+	}
+	for _, r := range w.Rules {
+		if strings.EqualFold(r, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Notifier posts alerts to webhooks, retrying transient failures and
+// holding each rule back for its cooldown after it notifies.
+type Notifier struct {
+	Client  *http.Client
+	Retries int           // extra attempts after a failed POST
+	Backoff time.Duration // wait before the first retry, doubled each time
+
+	// DryRun writes each payload to Out instead of posting it.
+	DryRun bool
+	Out    io.Writer
+
+	hooks     []Webhook
+	cooldowns map[string]time.Duration
+	last      map[string]time.Time
+}
+
+// NewNotifier creates a notifier for hooks, taking cooldowns from rules.
+func NewNotifier(hooks []Webhook, rules []Rule) (*Notifier, error) {
+	n := &Notifier{
+		Client:    &http.Client{Timeout: 10 * time.Second},
+		Retries:   3,
+		Backoff:   time.Second,
+		hooks:     hooks,
+		cooldowns: make(map[string]time.Duration),
+		last:      make(map[string]time.Time),
+	}
+	for _, h := range hooks {
+		if err := h.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range rules {
+		n.cooldowns[r.Name] = time.Duration(r.Cooldown)
+	}
+	return n, nil
+}
+
+// Notify sends alerts to every webhook that wants them. Alerts from a rule
+// still cooling down since its last notification are dropped; a rule's
+// cooldown starts once one of its alerts reaches a webhook. Errors from
+// individual webhooks are joined; the others are still tried.
+func (n *Notifier) Notify(ctx context.Context, alerts []Alert) error {
+	var send []Alert
+	for _, a := range alerts {
+		if last, ok := n.last[a.Rule]; ok && a.Time.Sub(last) < n.cooldowns[a.Rule] {
+			continue
+		}
+		send = append(send, a)
+	}
+
+	var errs []error
+	for _, h := range n.hooks {
+		var batch []Alert
+		for _, a := range send {
+			if h.wants(a.Rule) {
+				batch = append(batch, a)
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		for _, req := range requests(h.format(), batch) {
+			if err := n.post(ctx, h.URL, req.payload); err != nil {
+				errs = append(errs, err)
+				break
+			}
+			for _, a := range req.alerts {
+				if a.Time.After(n.last[a.Rule]) {
+					n.last[a.Rule] = a.Time
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// request is one request body and the alerts it carries.
+type request struct {
+	payload any
+	alerts  []Alert
+}
+
+// requests encodes a batch of alerts as one or more request bodies.
+func requests(format string, alerts []Alert) []request {
+	if format != FormatDiscord {
+		return []request{{newGenericPayload(alerts), alerts}}
+	}
+	var out []request
+	for start := 0; start < len(alerts); start += discordMaxEmbeds {
+		chunk := alerts[start:min(start+discordMaxEmbeds, len(alerts))]
+		out = append(out, request{newDiscordPayload(chunk), chunk})
+	}
+	return out
+}
+
+func (n *Notifier) post(ctx context.Context, target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if n.DryRun {
+		if n.Out != nil {
+			fmt.Fprintf(n.Out, "POST %s\n%s\n", target, body)
+		}
+		return nil
+	}
+
+	wait := n.Backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := n.postOnce(ctx, target, body)
+		if err == nil {
+			return nil
+		}
+		var perm permanentError
+		if errors.As(err, &perm) || attempt >= n.Retries {
+			return fmt.Errorf("webhook %s: %w", redact(target), err)
+		}
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// permanentError is a response retrying won't fix, such as a 400.
+type permanentError struct{ status string }
+
+func (e permanentError) Error() string { return e.status }
+
+// postOnce makes one attempt. Server errors and 429s are retryable; the
+// latter may say how long to wait.
+func (n *Notifier) postOnce(ctx context.Context, target string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, permanentError{err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gemcheck-tui")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		secs, _ := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+		return time.Duration(secs * float64(time.Second)), errors.New(resp.Status)
+	case resp.StatusCode >= 500:
+		return 0, errors.New(resp.Status)
+	}
+	return 0, permanentError{resp.Status}
+}
+
+// redact drops the path of a webhook URL, which is its secret, from errors.
+func redact(target string) string {
+	if u, err := url.Parse(target); err == nil {
+		return u.Scheme + "://" + u.Host + "/…"
+	}
+	return "(invalid URL)"
+}
+
+// genericPayload is the plain JSON format.
+type genericPayload struct {
+	Alerts []genericAlert `json:"alerts"`
+}
+
+type genericAlert struct {
+	Rule     string    `json:"rule"`
+	Kind     Kind      `json:"kind"`
+	Time     time.Time `json:"time"`
+	Color    string    `json:"color,omitempty"`
+	Gem      string    `json:"gem,omitempty"`
+	Value    float64   `json:"value"`
+	Previous float64   `json:"previous"`
+	Message  string    `json:"message"`
+}
+
+func newGenericPayload(alerts []Alert) genericPayload {
+	p := genericPayload{Alerts: make([]genericAlert, len(alerts))}
+	for i, a := range alerts {
+		p.Alerts[i] = genericAlert{
+			Rule: a.Rule, Kind: a.Kind, Time: a.Time.UTC(), Color: string(a.Color), Gem: a.Gem,
+			Value: a.Value, Previous: a.Previous, Message: a.Message,
+		}
+	}
+	return p
+}
+
+// discordPayload is a Discord webhook message with one embed per alert.
+type discordPayload struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Color       int            `json:"color,omitempty"`
+	Timestamp   string         `json:"timestamp"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// embedColors match the TUI's gem colors.
+var embedColors = map[domain.GemColor]int{
+	domain.Red:   0xf38ba8,
+	domain.Green: 0xa6e3a1,
+	domain.Blue:  0x89b4fa,
+}
+
+func newDiscordPayload(alerts []Alert) discordPayload {
+	p := discordPayload{Username: "gemcheck"}
+	for _, a := range alerts {
+		e := discordEmbed{
+			Title:       a.Rule,
+			Description: a.Message,
+			Color:       embedColors[a.Color],
+			Timestamp:   a.Time.UTC().Format(time.RFC3339),
+		}
+		if a.Color != "" {
+			e.Fields = append(e.Fields, discordField{Name: "Pool", Value: a.Color.Label(), Inline: true})
+		}
+		if a.Gem != "" {
+			e.Fields = append(e.Fields, discordField{Name: "Gem", Value: a.Gem, Inline: true})
+		}
+		e.Fields = append(e.Fields, discordField{Name: "Value", Value: domain.FormatChaos(a.Value), Inline: true})
+		p.Embeds = append(p.Embeds, e)
+	}
+	return p
+}
@@ -0,0 +1,248 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/duration"
+)
+
+// recorder is a webhook endpoint that answers with statuses in turn, then
+// 204, and keeps every body it receives.
+type recorder struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	rec.bodies = append(rec.bodies, body)
+	if r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	status := http.StatusNoContent
+	if len(rec.statuses) > 0 {
+		status, rec.statuses = rec.statuses[0], rec.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+var testAlerts = []Alert{
+	{
+		Rule: "red pool", Kind: PoolEV, Time: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Color: domain.Red, Value: 45, Previous: 40, Message: "Red pool EV 45.0c rose above 40.0c",
+	},
+	{
+		Rule: "carnage", Kind: PriceMove, Time: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Color: domain.Red, Gem: "Boneshatter of Carnage", Value: 130, Previous: 100,
+		Message: "Boneshatter of Carnage 100c → 130c (+30.0%)",
+	},
+}
+
+func newTestNotifier(t *testing.T, hooks []Webhook, rules []Rule) *Notifier {
+	t.Helper()
+	n, err := NewNotifier(hooks, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Backoff = time.Millisecond
+	return n
+}
+
+func TestNotifyGeneric(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newTestNotifier(t, []Webhook{{URL: srv.URL}}, nil)
+	if err := n.Notify(context.Background(), testAlerts); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(rec.bodies))
+	}
+
+	want := `{"alerts":[` +
+		`{"rule":"red pool","kind":"pool_ev","time":"2026-01-01T12:00:00Z","color":"r","value":45,"previous":40,"message":"Red pool EV 45.0c rose above 40.0c"},` +
+		`{"rule":"carnage","kind":"price_move","time":"2026-01-01T12:00:00Z","color":"r","gem":"Boneshatter of Carnage","value":130,"previous":100,"message":"Boneshatter of Carnage 100c → 130c (+30.0%)"}` +
+		`]}`
+	if got := string(rec.bodies[0]); got != want {
+		t.Errorf("payload:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNotifyDiscord(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	// Twelve alerts need two messages of at most ten embeds
+	var alerts []Alert
+	for range 6 {
+		alerts = append(alerts, testAlerts...)
+	}
+	n := newTestNotifier(t, []Webhook{{URL: srv.URL, Format: FormatDiscord}}, nil)
+	if err := n.Notify(context.Background(), alerts); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(rec.bodies))
+	}
+
+	var msg discordPayload
+	if err := json.Unmarshal(rec.bodies[0], &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Username != "gemcheck" || len(msg.Embeds) != discordMaxEmbeds {
+		t.Fatalf("unexpected message: username %q, %d embeds", msg.Username, len(msg.Embeds))
+	}
+	e := msg.Embeds[1]
+	if e.Title != "carnage" || e.Description != testAlerts[1].Message ||
+		e.Color != 0xf38ba8 || e.Timestamp != "2026-01-01T12:00:00Z" {
+		t.Errorf("unexpected embed: %+v", e)
+	}
+	if len(e.Fields) != 3 || e.Fields[1].Value != "Boneshatter of Carnage" || e.Fields[2].Value != "130c" {
+		t.Errorf("unexpected fields: %+v", e.Fields)
+	}
+
+	// The raw JSON uses Discord's field names
+	for _, key := range []string{`"embeds":`, `"title":`, `"description":`, `"timestamp":`, `"inline":true`} {
+		if !bytes.Contains(rec.bodies[0], []byte(key)) {
+			t.Errorf("payload missing %s", key)
+		}
+	}
+}
+
+func TestNotifyRetry(t *testing.T) {
+	rec := &recorder{statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newTestNotifier(t, []Webhook{{URL: srv.URL}}, nil)
+	if err := n.Notify(context.Background(), testAlerts[:1]); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if len(rec.bodies) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(rec.bodies))
+	}
+
+	// Client errors are not retried, and the URL path stays out of the error
+	rec = &recorder{statuses: []int{http.StatusBadRequest}}
+	srv2 := httptest.NewServer(rec)
+	defer srv2.Close()
+	n = newTestNotifier(t, []Webhook{{URL: srv2.URL + "/secret-token"}}, nil)
+	err := n.Notify(context.Background(), testAlerts[:1])
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("expected a redacted error, got %v", err)
+	}
+	if len(rec.bodies) != 1 {
+		t.Errorf("expected 1 attempt for a 400, got %d", len(rec.bodies))
+	}
+}
+
+func TestNotifyCooldownAndFilter(t *testing.T) {
+	all, carnage := &recorder{}, &recorder{}
+	srvAll, srvCarnage := httptest.NewServer(all), httptest.NewServer(carnage)
+	defer srvAll.Close()
+	defer srvCarnage.Close()
+
+	rules := []Rule{{Name: "red pool", Cooldown: duration.Duration(time.Hour)}, {Name: "carnage"}}
+	n := newTestNotifier(t, []Webhook{
+		{URL: srvAll.URL},
+		{URL: srvCarnage.URL, Rules: []string{"Carnage"}},
+	}, rules)
+
+	later := func(d time.Duration) []Alert {
+		var as []Alert
+		for _, a := range testAlerts {
+			a.Time = a.Time.Add(d)
+			as = append(as, a)
+		}
+		return as
+	}
+	for _, d := range []time.Duration{0, 30 * time.Minute, 61 * time.Minute} {
+		if err := n.Notify(context.Background(), later(d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// "red pool" is held back at +30m; "carnage" has no cooldown
+	var counts []int
+	for _, body := range all.bodies {
+		var p genericPayload
+		json.Unmarshal(body, &p)
+		counts = append(counts, len(p.Alerts))
+	}
+	if len(counts) != 3 || counts[0] != 2 || counts[1] != 1 || counts[2] != 2 {
+		t.Errorf("alerts per request = %v, want [2 1 2]", counts)
+	}
+	if len(carnage.bodies) != 3 || bytes.Contains(carnage.bodies[0], []byte("red pool")) {
+		t.Errorf("filtered webhook got %d requests: %s", len(carnage.bodies), carnage.bodies)
+	}
+}
+
+func TestNotifyFailedKeepsRuleReady(t *testing.T) {
+	rec := &recorder{statuses: []int{http.StatusBadRequest}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	rules := []Rule{{Name: "red pool", Cooldown: duration.Duration(time.Hour)}}
+	n := newTestNotifier(t, []Webhook{{URL: srv.URL}}, rules)
+	if err := n.Notify(context.Background(), testAlerts[:1]); err == nil {
+		t.Fatal("expected the rejected POST to fail")
+	}
+
+	// Nothing was delivered, so the next poll's alert isn't cooling down
+	next := testAlerts[0]
+	next.Time = next.Time.Add(5 * time.Minute)
+	if err := n.Notify(context.Background(), []Alert{next}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 2 {
+		t.Errorf("expected the alert to be sent again, got %d requests", len(rec.bodies))
+	}
+}
+
+func TestNotifyDryRun(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	var out bytes.Buffer
+	n := newTestNotifier(t, []Webhook{{URL: srv.URL}}, nil)
+	n.DryRun, n.Out = true, &out
+	if err := n.Notify(context.Background(), testAlerts); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.bodies) != 0 {
+		t.Errorf("dry run sent %d requests", len(rec.bodies))
+	}
+	if !strings.HasPrefix(out.String(), "POST "+srv.URL+"\n{\"alerts\":") {
+		t.Errorf("unexpected dry-run output: %q", out.String())
+	}
+}
+
+func TestWebhookFormat(t *testing.T) {
+	if f := (Webhook{URL: "https://discord.com/api/webhooks/1/abc"}).format(); f != FormatDiscord {
+		t.Errorf("discord URL format = %q", f)
+	}
+	if err := (Webhook{URL: "ftp://example.com"}).Validate(); err == nil {
+		t.Error("accepted an ftp URL")
+	}
+	if err := (Webhook{URL: "https://example.com", Format: "slack"}).Validate(); err == nil {
+		t.Error("accepted an unknown format")
+	}
+}
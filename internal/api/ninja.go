@@ -12,8 +12,9 @@ import (
 )
 
 const (
-	leaguesURL = "https://api.pathofexile.com/leagues?type=main&compact=1&game=poe1"
+	leaguesURL = "https://api.pathofexile.com/leagues?type=main&game=poe1"
 	ninjaAPI   = "https://poe.ninja/api/data/itemoverview"
+	ninjaIndex = "https://poe.ninja/api/data/getindexstate"
 	userAgent  = "gemcheck-tui/1.0"
 )
 
@@ -44,7 +45,14 @@ func FetchLeagues() ([]domain.League, error) {
 	}
 
 	var raw []struct {
-		ID string `json:"id"`
+		ID      string     `json:"id"`
+		Realm   string     `json:"realm"`
+		StartAt *time.Time `json:"startAt"`
+		EndAt   *time.Time `json:"endAt"`
+		Event   bool       `json:"event"`
+		Rules   []struct {
+			ID string `json:"id"`
+		} `json:"rules"`
 	}
 	if err := json.Unmarshal(body, &raw); err != nil {
 		return nil, fmt.Errorf("parsing leagues: %w", err)
@@ -52,7 +60,55 @@ func FetchLeagues() ([]domain.League, error) {
 
 	var leagues []domain.League
 	for _, l := range raw {
-		leagues = append(leagues, domain.League{ID: l.ID, Text: l.ID})
+		league := domain.League{ID: l.ID, Text: l.ID, Realm: l.Realm, Event: l.Event}
+		if l.StartAt != nil {
+			league.StartAt = *l.StartAt
+		}
+		if l.EndAt != nil {
+			league.EndAt = *l.EndAt
+		}
+		for _, r := range l.Rules {
+			switch r.ID {
+			case "Hardcore":
+				league.Hardcore = true
+			case "NoParties":
+				league.SSF = true
+			}
+		}
+		leagues = append(leagues, league)
+	}
+	return leagues, nil
+}
+
+// FetchNinjaLeagues returns the leagues poe.ninja indexes prices for. It has
+// no dates or SSF leagues, so it is only a fallback for FetchLeagues.
+func FetchNinjaLeagues() ([]domain.League, error) {
+	body, err := doGet(ninjaIndex)
+	if err != nil {
+		return nil, fmt.Errorf("fetching poe.ninja leagues: %w", err)
+	}
+
+	var resp struct {
+		EconomyLeagues []struct {
+			Name        string `json:"name"`
+			DisplayName string `json:"displayName"`
+			Hardcore    bool   `json:"hardcore"`
+		} `json:"economyLeagues"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing poe.ninja leagues: %w", err)
+	}
+
+	var leagues []domain.League
+	for _, l := range resp.EconomyLeagues {
+		text := l.DisplayName
+		if text == "" {
+			text = l.Name
+		}
+		leagues = append(leagues, domain.League{ID: l.Name, Text: text, Hardcore: l.Hardcore})
+	}
+	if len(leagues) == 0 {
+		return nil, fmt.Errorf("poe.ninja lists no leagues")
 	}
 	return leagues, nil
 }
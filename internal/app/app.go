@@ -1,31 +1,25 @@
 package app
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
-	"github.com/ovestokke/gemcheck-tui/internal/api"
-	"github.com/ovestokke/gemcheck-tui/internal/cache"
+	"github.com/ovestokke/gemcheck-tui/internal/config"
 	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/export"
+	"github.com/ovestokke/gemcheck-tui/internal/history"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+	"github.com/ovestokke/gemcheck-tui/internal/score"
+	"github.com/ovestokke/gemcheck-tui/internal/state"
 	"github.com/ovestokke/gemcheck-tui/internal/tui"
 	"github.com/ovestokke/gemcheck-tui/internal/tui/components"
-)
-
-// Cache TTLs
-const (
-	leagueTTL = 1 * time.Hour
-	wikiTTL   = 24 * time.Hour
-	priceTTL  = 5 * time.Minute
-)
-
-// Cache keys
-const (
-	keyLeagues = "leagues"
-	keyWiki    = "wiki"
-	keyPrices  = "prices"
+	"github.com/ovestokke/gemcheck-tui/internal/watchlist"
 )
 
 type screenState int
@@ -34,11 +28,18 @@ const (
 	screenLoading screenState = iota
 	screenLeagueSelect
 	screenMain
+	screenTrend
 )
 
+// deltaDisplay is how long changed rows keep their ▲/▼ markers after a refresh.
+const deltaDisplay = 10 * time.Minute
+
+// messageDisplay is how long status bar messages stay up.
+const messageDisplay = 10 * time.Second
+
 // Model is the top-level Bubble Tea model.
 type Model struct {
-	cache  *cache.Cache
+	loader *loader.Loader
 	screen screenState
 	width  int
 	height int
@@ -52,34 +53,222 @@ type Model struct {
 	statusbar    components.StatusBarModel
 	search       components.SearchModel
 	detail       components.DetailModel
+	lookup       components.LookupModel
+	sensitivity  components.SensitivityModel
+	trend        components.TrendModel
+	changes      components.ChangesModel
+	compare      components.CompareModel
+	filterBar    components.FilterBarModel
+	watchTab     components.WatchlistModel
+
+	// Ranking: built-in strategies followed by the user's scoring
+	// expressions. strategyErrs holds parse errors by strategy name.
+	strategies   []domain.RankStrategy
+	strategyErrs map[string]error
+	rank         int
+
+	excluded  domain.Exclusions
+	favorites map[string]bool
+	topN      int
+	exportCfg config.Export
+	statusGen int
+
+	// reload re-reads the config file, environment and flags.
+	reload func() (config.Config, error)
+
+	// configPath is the config file filter presets are saved to.
+	configPath string
+
+	// watch is the current league's watchlist, saved to watchPath once it
+	// has loaded from watchDir. Without a watchPath it is kept for the
+	// session only, and while watchLoading it can't be changed.
+	watch        watchlist.List
+	watchDir     string
+	watchPath    string
+	watchLoading bool
+	watchSaver   *watchlist.Saver
+
+	// saved is the state restored at start-up. Its league is opened once the
+	// league list arrives if it is listed, or regardless with forceLeague,
+	// and its gem highlighted once data is ready.
+	saved       state.State
+	openLeague  bool
+	forceLeague bool
+	restoreGem  bool
 
 	// Data
+	leagues    []domain.League
 	league     domain.League
+	pending    domain.League // league being loaded to switch to
+	compareTo  domain.League // league being loaded to compare with
+	loaded     map[string]leagueData
 	wiki       *domain.WikiData
 	prices     []domain.GemPrice
 	result     *domain.ProcessedResult
 	wikiReady  bool
 	priceReady bool
+
+	// Changes since the previous refresh. deltaGen counts refreshes so an
+	// expiry tick from an older refresh doesn't clear newer markers.
+	prevResult *domain.ProcessedResult
+	deltas     map[string]domain.GemDelta
+	deltaGen   int
+
+	// trendGen counts trend openings so a slow replay for an earlier gem
+	// doesn't replace the one on screen.
+	trendGen int
+
+	// Baselines for the changes overlay: 0 is the previous refresh, i > 0
+	// is snapshots[len-i], newest first. snapshotGen counts openings so a
+	// slow load for an earlier one is dropped.
+	snapshots   []history.Snapshot
+	baseline    int
+	snapshotGen int
 }
 
-// NewModel creates the application model.
-func NewModel(c *cache.Cache) Model {
-	return Model{
-		cache:     c,
-		screen:    screenLoading,
-		spinner:   components.NewSpinner("Fetching leagues..."),
-		tabs:      components.NewGemTabs(),
-		table:     components.NewGemTable(80, 20),
-		statusbar: components.NewStatusBar(),
-		search:    components.NewSearch(),
-		detail:    components.NewDetail(),
+// leagueData is a league switched away from, kept to switch back to at once.
+type leagueData struct {
+	prices     []domain.GemPrice
+	result     *domain.ProcessedResult
+	prevResult *domain.ProcessedResult
+}
+
+// NewModel creates the application model. cfg's keys must already have been
+// applied with tui.ApplyKeys; reload is called to pick up config changes.
+func NewModel(l *loader.Loader, cfg config.Config, reload func() (config.Config, error)) Model {
+	m := Model{
+		loader:      l,
+		screen:      screenLoading,
+		spinner:     components.NewSpinner("Fetching leagues..."),
+		tabs:        components.NewGemTabs(),
+		table:       components.NewGemTable(80, 20),
+		statusbar:   components.NewStatusBar(),
+		search:      components.NewSearch(),
+		detail:      components.NewDetail(),
+		lookup:      components.NewLookup(),
+		sensitivity: components.NewSensitivity(),
+		trend:       components.NewTrend(),
+		changes:     components.NewChanges(),
+		compare:     components.NewCompare(),
+		filterBar:   components.NewFilterBar(),
+		watchTab:    components.NewWatchlist(80, 20),
+		watchSaver:  watchlist.NewSaver(),
+		reload:      reload,
+		loaded:      make(map[string]leagueData),
 	}
+	m.applyConfig(cfg)
+	return m
+}
+
+// applyConfig takes on everything in cfg except the keys and cache dir.
+func (m *Model) applyConfig(cfg config.Config) {
+	custom, errs := score.Strategies(cfg.Scores)
+	m.strategies = append(append([]domain.RankStrategy{}, domain.RankStrategies...), custom...)
+	m.strategyErrs = errs
+	if m.rank >= len(m.strategies) {
+		m.rank = 0
+	}
+	m.excluded = domain.NewExclusions(cfg.Exclude)
+	m.favorites = make(map[string]bool, len(cfg.Favorites))
+	for _, name := range cfg.Favorites {
+		m.favorites[name] = true
+	}
+	m.filterBar.SetPresets(cfg.Filters)
+	m.topN = cfg.TopN
+	m.exportCfg = cfg.Export
+	tui.PriceTierHigh, tui.PriceTierMid = cfg.PriceTiers.High, cfg.PriceTiers.Mid
+	m.loader.SetTTL(cfg.TTL.Loader())
+}
+
+// reloadConfig re-reads the config and reprices the current data with it.
+func (m *Model) reloadConfig() {
+	cfg, err := m.reload()
+	if err == nil {
+		err = tui.ApplyKeys(cfg.Keys)
+	}
+	if err != nil {
+		// Only the first line fits; the rest is in `gemcheck help`
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		m.statusbar.SetMessage("Config not reloaded: "+msg, true)
+		return
+	}
+	m.applyConfig(cfg)
+	if m.wiki != nil && m.prices != nil {
+		result := domain.ProcessGems(*m.wiki, m.prices, m.topN, m.excluded)
+		m.result = &result
+		m.search.SetGems(result.GemPicks)
+		m.populateTable()
+		for id, d := range m.loaded {
+			result := domain.ProcessGems(*m.wiki, d.prices, m.topN, m.excluded)
+			m.loaded[id] = leagueData{prices: d.prices, result: &result}
+		}
+	}
+	m.statusbar.SetMessage("Config reloaded", false)
+}
+
+// Restore picks up where a previous session left off: s's tab and ranking
+// now, and its league and highlighted gem once they load. A league that no
+// longer exists falls back to league selection.
+func (m *Model) Restore(s state.State) {
+	m.saved = s
+	m.tabs.SetTab(s.Tab)
+	for i, st := range m.strategies {
+		if st.Name == s.Rank {
+			m.rank = i
+		}
+	}
+	m.table.SetStrategy(m.strategies[m.rank])
+	if s.Sort != "" {
+		m.table.SetSort(s.Sort, s.SortAsc)
+	} else {
+		m.table.SortByStrategy()
+	}
+	m.filterBar.SetFilter(s.Filter, s.Preset)
+	m.openLeague = s.League != ""
+	m.restoreGem = s.Gem != ""
+}
+
+// SetConfigPath sets the config file that filter presets are saved to.
+func (m *Model) SetConfigPath(path string) {
+	m.configPath = path
+}
+
+// SetWatchlistDir sets the directory league watchlists are kept in.
+func (m *Model) SetWatchlistDir(dir string) {
+	m.watchDir = dir
+}
+
+// OpenLeague opens league id at start-up even if it isn't in the league
+// list, e.g. a private league.
+func (m *Model) OpenLeague(id string) {
+	m.saved.League = id
+	m.openLeague = true
+	m.forceLeague = true
+}
+
+// State returns the UI state to save on quit. Until a league has loaded it
+// is the restored state, so quitting from league selection forgets nothing.
+func (m Model) State() state.State {
+	if m.result == nil {
+		return m.saved
+	}
+	s := state.State{
+		League: m.league.ID,
+		Tab:    m.tabs.ActiveTab,
+		Rank:   m.strategies[m.rank].Name,
+	}
+	s.Sort, s.SortAsc = m.table.Sort()
+	s.Filter, s.Preset = m.filterBar.Filter()
+	if e := m.table.SelectedEntry(); e != nil {
+		s.Gem = e.BaseName
+	}
+	return s
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Init(),
-		fetchLeaguesCmd(m.cache),
+		fetchLeaguesCmd(m.loader),
 	)
 }
 
@@ -88,35 +277,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.table.SetSize(msg.Width, msg.Height-4) // tabs + statusbar
+		m.filterBar.SetWidth(msg.Width)
+		m.resizeTable()
 		m.statusbar.SetWidth(msg.Width)
 		m.search.SetSize(msg.Width, msg.Height)
 		m.detail.SetSize(msg.Width, msg.Height)
-		if m.screen == screenLeagueSelect {
+		m.lookup.SetSize(msg.Width, msg.Height)
+		m.sensitivity.SetSize(msg.Width, msg.Height)
+		m.trend.SetSize(msg.Width, msg.Height)
+		m.changes.SetSize(msg.Width, msg.Height)
+		m.compare.SetSize(msg.Width, msg.Height)
+		if m.screen == screenLeagueSelect || m.leagueSelect.Active() {
 			m.leagueSelect, _ = m.leagueSelect.Update(msg)
 		}
 		return m, nil
 
 	case tui.LeaguesFetchedMsg:
-		if msg.Err != nil {
-			m.err = msg.Err
-			return m, nil
+		// With no league list at all, leagues can still be entered by ID
+		m.leagues = msg.Leagues
+		if m.openLeague {
+			m.openLeague = false
+			if l, ok := m.startLeague(); ok {
+				return m, func() tea.Msg { return tui.LeagueSelectedMsg{League: l} }
+			}
 		}
 		m.leagueSelect = components.NewLeagueSelect(msg.Leagues, m.width, m.height)
+		if msg.Err != nil {
+			m.leagueSelect.SetError(msg.Err)
+		}
 		m.screen = screenLeagueSelect
 		return m, nil
 
 	case tui.LeagueSelectedMsg:
+		if msg.Compare {
+			return m, m.compareLeague(msg.League)
+		}
+		if m.result != nil {
+			return m, m.switchLeague(msg.League)
+		}
 		m.league = msg.League
 		m.screen = screenLoading
 		m.spinner = components.NewSpinner("Loading gem data...")
 		m.wikiReady = false
 		m.priceReady = false
+		m.result, m.prevResult, m.deltas, m.snapshots = nil, nil, nil, nil
+		m.baseline = 0
 		m.statusbar.SetLeague(m.league.Text)
+		loadWatch := m.loadWatch(m.league.ID)
 		return m, tea.Batch(
 			m.spinner.Init(),
-			fetchWikiCmd(m.cache),
-			fetchPricesCmd(m.cache, m.league.ID),
+			fetchWikiCmd(m.loader),
+			fetchPricesCmd(m.loader, m.league.ID),
+			loadWatch,
 		)
 
 	case tui.WikiFetchedMsg:
@@ -129,6 +341,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.tryProcessGems()
 
 	case tui.PricesFetchedMsg:
+		if l, ok := m.background(msg.League); ok {
+			if msg.Err != nil {
+				m.statusbar.SetMessage("Couldn't load "+l.Text+": "+msg.Err.Error(), true)
+				if m.pending.ID == l.ID {
+					m.pending = domain.League{}
+				}
+				if m.compareTo.ID == l.ID {
+					m.compareTo = domain.League{}
+				}
+				return m, m.clearStatusCmd()
+			}
+			return m, processCmd(*m.wiki, msg.League, msg.Prices, m.topN, m.excluded)
+		}
+		if msg.League != m.league.ID {
+			return m, nil
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, nil
@@ -138,10 +366,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.tryProcessGems()
 
 	case tui.DataReadyMsg:
+		if _, ok := m.background(msg.League); ok {
+			return m, m.backgroundLoaded(msg)
+		}
+		if msg.League != m.league.ID {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		if m.result != nil {
+			m.prevResult = m.result
+			m.deltas = make(map[string]domain.GemDelta)
+			for _, d := range domain.DiffResults(*m.prevResult, msg.Result) {
+				m.deltas[d.BaseName] = d
+			}
+			m.deltaGen++
+			gen := m.deltaGen
+			cmd = tea.Tick(deltaDisplay, func(time.Time) tea.Msg {
+				return tui.DeltasExpiredMsg{Gen: gen}
+			})
+		}
 		m.result = &msg.Result
 		m.search.SetGems(msg.Result.GemPicks)
 		m.populateTable()
+		if m.restoreGem {
+			m.restoreGem = false
+			m.table.Select(m.saved.Gem)
+		}
 		m.screen = screenMain
+		return m, cmd
+
+	case tui.DeltasExpiredMsg:
+		if msg.Gen == m.deltaGen {
+			m.deltas = nil
+			m.populateTable()
+		}
+		return m, nil
+
+	case tui.ExportedMsg:
+		if msg.Err != nil {
+			m.statusbar.SetMessage("Export failed: "+msg.Err.Error(), true)
+		} else {
+			m.statusbar.SetMessage("Exported "+msg.Path, false)
+		}
+		return m, m.clearStatusCmd()
+
+	case tui.SaveFilterMsg:
+		return m, saveFilterCmd(m.configPath, msg.Name, msg.Filter)
+
+	case tui.FilterSavedMsg:
+		if msg.Err != nil {
+			m.statusbar.SetMessage("Filter not saved: "+msg.Err.Error(), true)
+		} else {
+			m.filterBar.AddPreset(msg.Name, msg.Filter)
+			m.statusbar.SetMessage("Saved filter preset "+msg.Name, false)
+		}
+		return m, m.clearStatusCmd()
+
+	case tui.WatchlistLoadedMsg:
+		if msg.League != m.league.ID {
+			return m, nil
+		}
+		m.watchLoading = false
+		if msg.Err != nil {
+			// Keep changes for the session rather than overwrite the file
+			m.statusbar.SetMessage("Watchlist not loaded, changes won't be saved: "+msg.Err.Error(), true)
+			return m, m.clearStatusCmd()
+		}
+		m.watch = msg.List
+		m.watchPath = watchlist.Path(m.watchDir, msg.League)
+		m.populateTable()
+		return m, nil
+
+	case tui.WatchlistSavedMsg:
+		if msg.Err != nil {
+			m.statusbar.SetMessage("Watchlist not saved: "+msg.Err.Error(), true)
+			return m, m.clearStatusCmd()
+		}
+		return m, nil
+
+	case tui.StatusClearMsg:
+		if msg.Gen == m.statusGen {
+			m.statusbar.ClearMessage()
+		}
+		return m, nil
+
+	case tui.SnapshotsLoadedMsg:
+		if msg.League != m.league.ID || msg.Gen != m.snapshotGen {
+			return m, nil
+		}
+		// Errors just leave the previous refresh as the only baseline
+		m.snapshots = msg.Snapshots
+		m.baseline = min(m.baseline, len(m.snapshots))
+		if m.changes.Active() {
+			m.showChanges()
+		}
+		return m, nil
+
+	case tui.TrendLoadedMsg:
+		if msg.Gen == m.trendGen {
+			m.trend.SetData(msg)
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -155,11 +479,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 	case screenLeagueSelect:
 		m.leagueSelect, cmd = m.leagueSelect.Update(msg)
+	case screenTrend:
+		m.trend, cmd = m.trend.Update(msg)
 	case screenMain:
-		if m.search.Active() {
+		if m.leagueSelect.Active() {
+			m.leagueSelect, cmd = m.leagueSelect.Update(msg)
+		} else if m.compare.Active() {
+			m.compare, cmd = m.compare.Update(msg)
+		} else if m.search.Active() {
 			m.search, cmd = m.search.Update(msg)
 		} else if m.detail.Active() {
 			m.detail, cmd = m.detail.Update(msg)
+		} else if m.lookup.Active() {
+			m.lookup, cmd = m.lookup.Update(msg)
+		} else if m.sensitivity.Active() {
+			m.sensitivity, cmd = m.sensitivity.Update(msg)
+		} else if m.changes.Active() {
+			m.changes, cmd = m.changes.Update(msg)
+		} else if m.filterBar.Active() {
+			m.filterBar, cmd = m.filterBar.Update(msg)
+		} else if m.tabs.Watchlist() {
+			m.watchTab, cmd = m.watchTab.Update(msg)
 		} else {
 			m.table, cmd = m.table.Update(msg)
 		}
@@ -169,7 +509,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Global quit
-	if key.Matches(msg, tui.Keys.Quit) && !m.search.Active() {
+	if key.Matches(msg, tui.Keys.Quit) && !m.search.Active() && !m.leagueSelect.Filtering() && !m.filterBar.Typing() {
 		return m, tea.Quit
 	}
 
@@ -179,14 +519,38 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.screen == screenTrend {
+		if key.Matches(msg, tui.Keys.Back) || key.Matches(msg, tui.Keys.Trend) {
+			m.screen = screenMain
+		}
+		return m, nil
+	}
+
 	if m.screen != screenMain {
 		return m, nil
 	}
 
+	// League picker
+	if m.leagueSelect.Active() {
+		var cmd tea.Cmd
+		m.leagueSelect, cmd = m.leagueSelect.Update(msg)
+		return m, cmd
+	}
+
+	// League comparison
+	if m.compare.Active() {
+		m.compare, _ = m.compare.Update(msg)
+		return m, nil
+	}
+
 	// Search overlay takes priority
 	if m.search.Active() {
 		if msg.String() == "enter" {
-			if entry := m.search.SelectedEntry(); entry != nil {
+			// A transfigured gem opens its reverse lookup, a base gem its detail
+			if l, ok := domain.LookupVariant(*m.result, m.search.SelectedVariant()); ok {
+				m.search.Close()
+				m.lookup.Show(l)
+			} else if entry := m.search.SelectedEntry(); entry != nil {
 				m.search.Close()
 				m.detail.Show(entry)
 			}
@@ -197,14 +561,74 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	// Detail overlay
+	// Detail overlay: the watch key watches the variant under the cursor
 	if m.detail.Active() {
+		if key.Matches(msg, tui.Keys.Watch) {
+			if v, ok := m.detail.SelectedVariant(); ok {
+				return m, m.toggleWatch(watchlist.Item{Name: v.Name, Variant: true, Value: v.SellPrice})
+			}
+			return m, nil
+		}
 		m.detail, _ = m.detail.Update(msg)
 		return m, nil
 	}
 
+	// Reverse lookup: enter goes on to the base gem's detail
+	if m.lookup.Active() {
+		switch {
+		case key.Matches(msg, tui.Keys.Select):
+			m.lookup.Hide()
+			if e, ok := m.result.Entry(m.lookup.BaseName()); ok {
+				m.detail.Show(&e)
+			}
+			return m, nil
+		case key.Matches(msg, tui.Keys.Watch):
+			v := m.lookup.Variant()
+			return m, m.toggleWatch(watchlist.Item{Name: v.Name, Variant: true, Value: v.SellPrice})
+		}
+		m.lookup, _ = m.lookup.Update(msg)
+		return m, nil
+	}
+
+	// Pool EV drivers overlay
+	if m.sensitivity.Active() {
+		m.sensitivity, _ = m.sensitivity.Update(msg)
+		return m, nil
+	}
+
+	// Changes overlay: ←/→ step through baselines
+	if m.changes.Active() {
+		switch msg.String() {
+		case "left", "h", "[":
+			if m.baseline < len(m.snapshots) {
+				m.baseline++
+				m.showChanges()
+			}
+		case "right", "l", "]":
+			if m.baseline > 0 {
+				m.baseline--
+				m.showChanges()
+			}
+		default:
+			m.changes, _ = m.changes.Update(msg)
+		}
+		return m, nil
+	}
+
+	// Filter bar: edits apply as they are made
+	if m.filterBar.Active() {
+		var cmd tea.Cmd
+		m.filterBar, cmd = m.filterBar.Update(msg)
+		m.populateTable()
+		return m, cmd
+	}
+
 	// Normal main screen keys
 	switch {
+	case m.tabs.Watchlist() && key.Matches(msg, tui.Keys.Sort, tui.Keys.SortReverse,
+		tui.Keys.Filter, tui.Keys.Export, tui.Keys.Drivers):
+		m.statusbar.SetMessage("Not available on the watchlist tab", false)
+		return m, m.clearStatusCmd()
 	case key.Matches(msg, tui.Keys.Tab1):
 		m.tabs.SetTab(0)
 		m.populateTable()
@@ -214,42 +638,314 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, tui.Keys.Tab3):
 		m.tabs.SetTab(2)
 		m.populateTable()
+	case key.Matches(msg, tui.Keys.Tab4):
+		m.tabs.SetTab(3)
+		m.populateTable()
 	case key.Matches(msg, tui.Keys.NextTab):
 		m.tabs.NextTab()
 		m.populateTable()
 	case key.Matches(msg, tui.Keys.Search):
 		return m, m.search.Open()
 	case key.Matches(msg, tui.Keys.Refresh):
-		m.cache.Clear(keyPrices)
+		m.loader.Cache.Clear(loader.PricesKey(m.league.ID))
 		m.screen = screenLoading
 		m.spinner = components.NewSpinner("Refreshing prices...")
 		m.priceReady = false
 		m.wikiReady = true // wiki is still valid
 		return m, tea.Batch(
 			m.spinner.Init(),
-			fetchPricesCmd(m.cache, m.league.ID),
+			fetchPricesCmd(m.loader, m.league.ID),
 		)
 	case key.Matches(msg, tui.Keys.Select):
-		if entry := m.table.SelectedEntry(); entry != nil {
+		// A watched transfigured gem opens its reverse lookup
+		if it, _, ok := m.watchTab.Selected(); ok && m.tabs.Watchlist() && it.Variant {
+			if l, ok := domain.LookupVariant(*m.result, it.Name); ok {
+				m.lookup.Show(l)
+			}
+		} else if entry := m.selectedEntry(); entry != nil {
 			m.detail.Show(entry)
 		}
+	case key.Matches(msg, tui.Keys.Watch):
+		if m.tabs.Watchlist() {
+			if it, _, ok := m.watchTab.Selected(); ok {
+				return m, m.toggleWatch(it)
+			}
+		} else if e := m.table.SelectedEntry(); e != nil {
+			return m, m.toggleWatch(watchlist.Item{Name: e.BaseName, Value: e.EV})
+		}
+	case key.Matches(msg, tui.Keys.Rank):
+		m.rank = (m.rank + 1) % len(m.strategies)
+		m.table.SetStrategy(m.strategies[m.rank])
+		m.table.SortByStrategy()
+		m.populateTable()
+	case key.Matches(msg, tui.Keys.Sort):
+		m.table.NextSort()
+	case key.Matches(msg, tui.Keys.SortReverse):
+		m.table.ReverseSort()
+	case key.Matches(msg, tui.Keys.Filter):
+		m.filterBar.Open()
+		m.resizeTable()
+	case key.Matches(msg, tui.Keys.Trend):
+		m.screen = screenTrend
+		m.trendGen++
+		entry := m.selectedEntry()
+		return m, tea.Batch(
+			m.trend.Open(m.league.Text, entry),
+			loadTrendCmd(m.loader.History, m.trendGen, m.league.ID, *m.wiki, m.excluded, entry),
+		)
+	case key.Matches(msg, tui.Keys.Export):
+		return m, m.exportCmd()
+	case key.Matches(msg, tui.Keys.League):
+		m.leagueSelect = components.NewLeaguePicker(m.leagues, m.league.ID, m.loadedIDs(), m.width, m.height)
+	case key.Matches(msg, tui.Keys.Compare):
+		m.leagueSelect = components.NewComparePicker(m.leagues, m.league.ID, m.loadedIDs(), m.width, m.height)
+	case key.Matches(msg, tui.Keys.Reload):
+		m.reloadConfig()
+		return m, m.clearStatusCmd()
+	case key.Matches(msg, tui.Keys.Changes):
+		m.baseline = 0
+		m.snapshotGen++
+		m.showChanges()
+		return m, loadSnapshotsCmd(m.loader, m.snapshotGen, m.league.ID)
+	case key.Matches(msg, tui.Keys.Drivers):
+		color := m.tabs.ActiveColor()
+		if stats, ok := m.result.ColorStats[color]; ok {
+			m.sensitivity.Show(stats, domain.PoolSensitivity(*m.result, color))
+		}
 	default:
 		var cmd tea.Cmd
-		m.table, cmd = m.table.Update(msg)
+		if m.tabs.Watchlist() {
+			m.watchTab, cmd = m.watchTab.Update(msg)
+		} else {
+			m.table, cmd = m.table.Update(msg)
+		}
 		return m, cmd
 	}
 	return m, nil
 }
 
+// selectedEntry returns the highlighted gem: the table's, or on the
+// Watchlist tab the base gem of the watched gem, if it is still priced.
+func (m *Model) selectedEntry() *domain.GemEntry {
+	if !m.tabs.Watchlist() {
+		return m.table.SelectedEntry()
+	}
+	if _, base, ok := m.watchTab.Selected(); ok {
+		if e, ok := m.result.Entry(base); ok {
+			return &e
+		}
+	}
+	return nil
+}
+
+// toggleWatch watches it, or stops watching it if it already is, and saves
+// the league's watchlist.
+func (m *Model) toggleWatch(it watchlist.Item) tea.Cmd {
+	if m.watchLoading {
+		m.statusbar.SetMessage("Watchlist still loading", false)
+		return m.clearStatusCmd()
+	}
+	it.Added = time.Now()
+	if m.watch.Toggle(it) {
+		m.statusbar.SetMessage("Watching "+it.Name, false)
+	} else {
+		m.statusbar.SetMessage("Stopped watching "+it.Name, false)
+	}
+	m.populateTable()
+	var save tea.Cmd
+	if m.watchPath != "" {
+		save = saveWatchlistCmd(m.watchSaver, m.watchPath, m.watch)
+	}
+	return tea.Batch(save, m.clearStatusCmd())
+}
+
+// loadWatch empties the watchlist for league and returns the command
+// loading its saved one. Toggles wait for it, so it can't overwrite them.
+func (m *Model) loadWatch(league string) tea.Cmd {
+	m.watch, m.watchPath = watchlist.List{}, ""
+	m.watchLoading = m.watchDir != ""
+	if !m.watchLoading {
+		return nil
+	}
+	return loadWatchlistCmd(m.watchDir, league)
+}
+
+// startLeague returns the league to open at start-up, if it is listed or
+// was asked for explicitly.
+func (m *Model) startLeague() (domain.League, bool) {
+	for _, l := range m.leagues {
+		if strings.EqualFold(l.ID, m.saved.League) {
+			return l, true
+		}
+	}
+	if m.forceLeague {
+		return domain.League{ID: m.saved.League, Text: m.saved.League}, true
+	}
+	return domain.League{}, false
+}
+
 func (m *Model) tryProcessGems() tea.Cmd {
 	if !m.wikiReady || !m.priceReady {
 		return nil
 	}
-	wiki := m.wiki
-	prices := m.prices
+	return processCmd(*m.wiki, m.league.ID, m.prices, m.topN, m.excluded)
+}
+
+// switchLeague changes league from the main screen. The current league stays
+// up until the new one has loaded, and leagues loaded before switch back at
+// once.
+func (m *Model) switchLeague(l domain.League) tea.Cmd {
+	if l.ID == m.league.ID {
+		m.pending = domain.League{}
+		return nil
+	}
+	if d, ok := m.loaded[l.ID]; ok {
+		m.pending = domain.League{}
+		cmd := m.showLeague(l, d)
+		m.statusbar.SetMessage("Switched to "+l.Text, false)
+		return tea.Batch(cmd, m.clearStatusCmd())
+	}
+	m.pending = l
+	m.statusbar.SetMessage("Loading "+l.Text+"...", false)
+	m.statusGen++ // not cleared until the league loads
+	return fetchPricesCmd(m.loader, l.ID)
+}
+
+// compareLeague opens the comparison of the current league with l, loading
+// l first if it hasn't been.
+func (m *Model) compareLeague(l domain.League) tea.Cmd {
+	if l.ID == m.league.ID {
+		m.statusbar.SetMessage("Pick another league to compare "+l.Text+" with", false)
+		return m.clearStatusCmd()
+	}
+	if d, ok := m.loaded[l.ID]; ok {
+		m.showCompare(l, d.result)
+		return nil
+	}
+	m.compareTo = l
+	m.statusbar.SetMessage("Loading "+l.Text+" to compare...", false)
+	m.statusGen++ // not cleared until the league loads
+	return fetchPricesCmd(m.loader, l.ID)
+}
+
+func (m *Model) showCompare(l domain.League, r *domain.ProcessedResult) {
+	color := m.tabs.ActiveColor()
+	if color == "" {
+		color = domain.Red
+	}
+	m.compare.Show(m.league.Text, l.Text, domain.CompareLeagues(*m.result, *r), color)
+}
+
+// background returns the league with id if it is loading to switch to or to
+// compare with.
+func (m *Model) background(id string) (domain.League, bool) {
+	switch id {
+	case "":
+		return domain.League{}, false
+	case m.pending.ID:
+		return m.pending, true
+	case m.compareTo.ID:
+		return m.compareTo, true
+	}
+	return domain.League{}, false
+}
+
+// backgroundLoaded takes a league loaded by switchLeague or compareLeague.
+func (m *Model) backgroundLoaded(msg tui.DataReadyMsg) tea.Cmd {
+	d := leagueData{prices: msg.Prices, result: &msg.Result}
+	m.statusbar.ClearMessage()
+	if l := m.compareTo; l.ID == msg.League {
+		m.compareTo = domain.League{}
+		m.loaded[l.ID] = d
+		m.showCompare(l, d.result)
+	}
+	if l := m.pending; l.ID == msg.League {
+		m.pending = domain.League{}
+		cmd := m.showLeague(l, d)
+		m.statusbar.SetMessage("Switched to "+l.Text, false)
+		return tea.Batch(cmd, m.clearStatusCmd())
+	}
+	return nil
+}
+
+// loadedIDs returns the leagues held in memory besides the current one.
+func (m *Model) loadedIDs() map[string]bool {
+	ids := make(map[string]bool, len(m.loaded))
+	for id := range m.loaded {
+		ids[id] = true
+	}
+	return ids
+}
+
+// showLeague makes l, with data d, the current league and keeps the outgoing
+// league's data to switch back to. It returns the command loading l's
+// watchlist.
+func (m *Model) showLeague(l domain.League, d leagueData) tea.Cmd {
+	m.loaded[m.league.ID] = leagueData{prices: m.prices, result: m.result, prevResult: m.prevResult}
+	delete(m.loaded, l.ID)
+
+	m.league = l
+	m.prices, m.result, m.prevResult = d.prices, d.result, d.prevResult
+	m.deltas, m.snapshots, m.baseline = nil, nil, 0
+	m.deltaGen++
+	cmd := m.loadWatch(l.ID)
+	m.search.SetGems(m.result.GemPicks)
+	m.statusbar.SetLeague(l.Text)
+	m.populateTable()
+	return cmd
+}
+
+// showChanges opens the changes overlay against the selected baseline.
+func (m *Model) showChanges() {
+	count := len(m.snapshots) + 1
+	if m.baseline == 0 {
+		if m.prevResult == nil {
+			m.changes.ShowMessage("last refresh", 0, count,
+				"Nothing to compare yet. Press r to refresh, or ← for a saved snapshot.")
+			return
+		}
+		m.changes.Show("last refresh", 0, count,
+			domain.DiffResults(*m.prevResult, *m.result), domain.PoolChanges(*m.prevResult, *m.result))
+		return
+	}
+	snap := m.snapshots[len(m.snapshots)-m.baseline]
+	old := domain.ProcessGems(*m.wiki, snap.GemPrices(), m.topN, m.excluded)
+	label := snap.Time.Local().Format("Jan 02 15:04")
+	m.changes.Show(label, m.baseline, count,
+		domain.DiffResults(old, *m.result), domain.PoolChanges(old, *m.result))
+}
+
+// clearStatusCmd clears the status bar message after messageDisplay, unless
+// another message replaced it first.
+func (m *Model) clearStatusCmd() tea.Cmd {
+	m.statusGen++
+	gen := m.statusGen
+	return tea.Tick(messageDisplay, func(time.Time) tea.Msg {
+		return tui.StatusClearMsg{Gen: gen}
+	})
+}
+
+// exportCmd writes the active tab's gems, in the table's order, to a
+// timestamped file in the configured export directory.
+func (m *Model) exportCmd() tea.Cmd {
+	f, err := export.ParseFormat(m.exportCfg.Format)
+	if err != nil {
+		return func() tea.Msg { return tui.ExportedMsg{Err: err} }
+	}
+	color := m.tabs.ActiveColor()
+	gems := m.table.Entries()
+	strategy := m.strategies[m.rank]
+	var rank *domain.RankStrategy
+	if m.rank > 0 {
+		rank = &strategy
+	}
+	t := export.GemsTable(gems, rank)
+
+	dir, league := m.exportCfg.Dir, m.league.ID
+	label := strings.ToLower(color.Label())
 	return func() tea.Msg {
-		result := domain.ProcessGems(*wiki, prices, 10)
-		return tui.DataReadyMsg{Result: result}
+		path, err := export.WriteFile(dir, league, label, f, t, time.Now())
+		return tui.ExportedMsg{Path: path, Err: err}
 	}
 }
 
@@ -258,17 +954,68 @@ func (m *Model) populateTable() {
 		return
 	}
 	activeColor := m.tabs.ActiveColor()
-	m.table.SetEntries(m.result.GemPicks, activeColor)
-	age := m.cache.Age(keyPrices, priceTTL)
+	strategy := m.strategies[m.rank]
+	m.table.SetStrategy(strategy)
+	m.table.SetDeltas(m.deltas)
+
+	watched := m.watch.Names()
+	m.table.SetWatched(watched)
+	m.detail.SetWatched(watched)
+	m.lookup.SetWatched(watched)
+	m.watchTab.SetItems(m.watch.Items, *m.result)
+
+	// The filter applies to every color tab
+	ranked := domain.RankGems(m.result.GemPicks, strategy)
+	filter, _ := m.filterBar.Filter()
+	shown := domain.FilterGems(ranked, filter, m.favoriteSet())
+	m.table.SetEntries(shown, activeColor)
+	m.filterBar.SetCounts(countColor(shown, activeColor), countColor(ranked, activeColor))
+	m.resizeTable()
+	m.tabs.SetStrategy(strategy.Name, m.strategyErrs[strategy.Name])
+	age := m.loader.Cache.Age(loader.PricesKey(m.league.ID), m.loader.TTL().Prices)
 	m.statusbar.SetCacheAge(age)
 
 	// Pass stats to tabs and status bar
 	if stats, ok := m.result.ColorStats[activeColor]; ok {
 		m.tabs.SetPoolStats(&stats, len(m.result.GemPicks))
+	} else {
+		m.tabs.SetPoolStats(nil, len(m.result.GemPicks))
 	}
 	m.statusbar.SetGemCount(len(m.result.GemPicks))
 }
 
+// resizeTable fits the table between the tabs, the filter bar when shown
+// and the status bar. The Watchlist tab has no filter bar.
+func (m *Model) resizeTable() {
+	m.table.SetSize(m.width, m.height-4-m.filterBar.Height())
+	m.watchTab.SetSize(m.width, m.height-4)
+}
+
+// favoriteSet is what the favorites filter keeps: the configured favorites
+// and the watched base gems.
+func (m *Model) favoriteSet() map[string]bool {
+	favs := make(map[string]bool, len(m.favorites)+len(m.watch.Items))
+	for name := range m.favorites {
+		favs[name] = true
+	}
+	for _, it := range m.watch.Items {
+		if !it.Variant {
+			favs[it.Name] = true
+		}
+	}
+	return favs
+}
+
+func countColor(entries []domain.GemEntry, color domain.GemColor) int {
+	n := 0
+	for _, e := range entries {
+		if e.Color == color {
+			n++
+		}
+	}
+	return n
+}
+
 func (m Model) View() string {
 	if m.err != nil {
 		return tui.StyleError.Render("Error: "+m.err.Error()) + "\n\n" +
@@ -283,17 +1030,35 @@ func (m Model) View() string {
 	case screenLeagueSelect:
 		return m.leagueSelect.View()
 
+	case screenTrend:
+		return m.trend.View()
+
 	case screenMain:
 		tabBar := m.tabs.View(m.width)
 		tableView := m.table.View()
 		statusBar := m.statusbar.View()
 
-		main := lipgloss.JoinVertical(lipgloss.Left, tabBar, tableView, statusBar)
+		rows := []string{tabBar}
+		if m.tabs.Watchlist() {
+			tableView = m.watchTab.View()
+		} else if m.filterBar.Height() > 0 {
+			rows = append(rows, m.filterBar.View())
+		}
+		main := lipgloss.JoinVertical(lipgloss.Left, append(rows, tableView, statusBar)...)
 
 		// Render main to full terminal size
 		mainPlaced := lipgloss.Place(m.width, m.height, lipgloss.Left, lipgloss.Top, main)
 
-		// Overlay search or detail on top using Place compositing
+		// Overlay popups on top using Place compositing, in the order
+		// handleKey gives them keys
+		if m.leagueSelect.Active() {
+			overlay := m.leagueSelect.View()
+			return overlayCenter(mainPlaced, overlay, m.width, m.height)
+		}
+		if m.compare.Active() {
+			overlay := m.compare.View()
+			return overlayCenter(mainPlaced, overlay, m.width, m.height)
+		}
 		if m.search.Active() {
 			overlay := m.search.View()
 			return overlayCenter(mainPlaced, overlay, m.width, m.height)
@@ -302,6 +1067,18 @@ func (m Model) View() string {
 			overlay := m.detail.View()
 			return overlayCenter(mainPlaced, overlay, m.width, m.height)
 		}
+		if m.lookup.Active() {
+			overlay := m.lookup.View()
+			return overlayCenter(mainPlaced, overlay, m.width, m.height)
+		}
+		if m.sensitivity.Active() {
+			overlay := m.sensitivity.View()
+			return overlayCenter(mainPlaced, overlay, m.width, m.height)
+		}
+		if m.changes.Active() {
+			overlay := m.changes.View()
+			return overlayCenter(mainPlaced, overlay, m.width, m.height)
+		}
 		return mainPlaced
 	}
 	return ""
@@ -316,50 +1093,99 @@ func overlayCenter(_, overlay string, _, _ int) string {
 
 // Async commands
 
-func fetchLeaguesCmd(c *cache.Cache) tea.Cmd {
+func fetchLeaguesCmd(l *loader.Loader) tea.Cmd {
 	return func() tea.Msg {
-		if data, ok := c.Get(keyLeagues); ok {
-			if leagues, ok := data.([]domain.League); ok {
-				return tui.LeaguesFetchedMsg{Leagues: leagues}
-			}
-		}
-		leagues, err := api.FetchLeagues()
-		if err != nil {
-			return tui.LeaguesFetchedMsg{Err: err}
-		}
-		c.Set(keyLeagues, leagues, leagueTTL)
-		return tui.LeaguesFetchedMsg{Leagues: leagues}
+		leagues, err := l.Leagues()
+		return tui.LeaguesFetchedMsg{Leagues: leagues, Err: err}
 	}
 }
 
-func fetchWikiCmd(c *cache.Cache) tea.Cmd {
+// saveFilterCmd saves f to the config file at path as preset name.
+func saveFilterCmd(path, name string, f domain.GemFilter) tea.Cmd {
 	return func() tea.Msg {
-		// Try disk cache first
-		var wiki domain.WikiData
-		if c.LoadFromDisk(keyWiki, &wiki) {
-			return tui.WikiFetchedMsg{Wiki: &wiki}
-		}
-		w, err := api.FetchWikiData()
-		if err != nil {
-			return tui.WikiFetchedMsg{Err: err}
+		err := errors.New("no config file path")
+		if path != "" {
+			err = config.SaveFilter(path, name, f)
 		}
-		c.SaveToDisk(keyWiki, w, wikiTTL)
-		return tui.WikiFetchedMsg{Wiki: w}
+		return tui.FilterSavedMsg{Name: name, Filter: f, Err: err}
 	}
 }
 
-func fetchPricesCmd(c *cache.Cache, league string) tea.Cmd {
+// loadWatchlistCmd reads league's watchlist from dir.
+func loadWatchlistCmd(dir, league string) tea.Cmd {
 	return func() tea.Msg {
-		if data, ok := c.Get(keyPrices); ok {
-			if prices, ok := data.([]domain.GemPrice); ok {
-				return tui.PricesFetchedMsg{Prices: prices}
-			}
+		l, err := watchlist.Load(watchlist.Path(dir, league))
+		return tui.WatchlistLoadedMsg{League: league, List: l, Err: err}
+	}
+}
+
+// saveWatchlistCmd writes watchlist l to path through saver, which drops it
+// if a later change has been saved first.
+func saveWatchlistCmd(saver *watchlist.Saver, path string, l watchlist.List) tea.Cmd {
+	version := saver.Version()
+	return func() tea.Msg {
+		return tui.WatchlistSavedMsg{Err: saver.Save(path, l, version)}
+	}
+}
+
+func fetchWikiCmd(l *loader.Loader) tea.Cmd {
+	return func() tea.Msg {
+		wiki, err := l.Wiki()
+		return tui.WikiFetchedMsg{Wiki: wiki, Err: err}
+	}
+}
+
+func fetchPricesCmd(l *loader.Loader, league string) tea.Cmd {
+	return func() tea.Msg {
+		prices, err := l.Prices(league)
+		return tui.PricesFetchedMsg{League: league, Prices: prices, Err: err}
+	}
+}
+
+func processCmd(wiki domain.WikiData, league string, prices []domain.GemPrice, topN int, excluded domain.Exclusions) tea.Cmd {
+	return func() tea.Msg {
+		result := domain.ProcessGems(wiki, prices, topN, excluded)
+		return tui.DataReadyMsg{League: league, Prices: prices, Result: result}
+	}
+}
+
+// loadSnapshotsCmd loads the league's snapshots, leaving out the one the
+// loader recorded for the prices on screen: it would show no changes.
+func loadSnapshotsCmd(l *loader.Loader, gen int, league string) tea.Cmd {
+	hist, current := l.History, l.Recorded(league)
+	return func() tea.Msg {
+		msg := tui.SnapshotsLoadedMsg{League: league, Gen: gen}
+		if hist == nil {
+			msg.Err = errors.New("price history is disabled")
+			return msg
 		}
-		prices, err := api.FetchGemPrices(league)
-		if err != nil {
-			return tui.PricesFetchedMsg{Err: err}
+		snaps, err := hist.Snapshots(league)
+		msg.Snapshots = slices.DeleteFunc(snaps, func(s history.Snapshot) bool {
+			return s.Time.Equal(current)
+		})
+		msg.Err = err
+		return msg
+	}
+}
+
+func loadTrendCmd(hist *history.Store, gen int, league string, wiki domain.WikiData, excluded domain.Exclusions, entry *domain.GemEntry) tea.Cmd {
+	var baseName string
+	if entry != nil {
+		baseName = entry.BaseName
+	}
+	return func() tea.Msg {
+		if hist == nil {
+			return tui.TrendLoadedMsg{Gen: gen, Err: errors.New("price history is disabled")}
 		}
-		c.Set(keyPrices, prices, priceTTL)
-		return tui.PricesFetchedMsg{Prices: prices}
+		msg := tui.TrendLoadedMsg{Gen: gen, Pools: make(map[domain.GemColor][]history.Point)}
+		msg.Err = hist.Replay(league, wiki, excluded, func(at time.Time, result domain.ProcessedResult) {
+			for _, c := range domain.AllColors {
+				msg.Pools[c] = append(msg.Pools[c], history.Point{Time: at, Value: result.ColorStats[c].PoolEV})
+			}
+			if e, ok := result.Entry(baseName); ok && baseName != "" {
+				msg.Entry = append(msg.Entry, history.Point{Time: at, Value: e.EV})
+			}
+		})
+		return msg
 	}
 }
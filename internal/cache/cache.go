@@ -13,11 +13,20 @@ type entry struct {
 	expiresAt time.Time
 }
 
+// Stats counts lookups of one key.
+type Stats struct {
+	Hits, Misses         uint64 // Get
+	DiskHits, DiskMisses uint64 // LoadFromDisk
+}
+
 // Cache provides in-memory TTL caching with optional disk persistence.
 type Cache struct {
 	mu      sync.RWMutex
 	items   map[string]entry
 	diskDir string
+
+	statsMu sync.Mutex
+	stats   map[string]*Stats
 }
 
 // New creates a cache. If diskDir is non-empty, disk persistence is enabled.
@@ -28,20 +37,51 @@ func New(diskDir string) *Cache {
 	return &Cache{
 		items:   make(map[string]entry),
 		diskDir: diskDir,
+		stats:   make(map[string]*Stats),
 	}
 }
 
 // Get retrieves a value from the cache. Returns nil if expired or missing.
 func (c *Cache) Get(key string) (any, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
 	e, ok := c.items[key]
-	if !ok || time.Now().After(e.expiresAt) {
+	c.mu.RUnlock()
+	hit := ok && !time.Now().After(e.expiresAt)
+	c.record(key, func(s *Stats) {
+		if hit {
+			s.Hits++
+		} else {
+			s.Misses++
+		}
+	})
+	if !hit {
 		return nil, false
 	}
 	return e.data, true
 }
 
+func (c *Cache) record(key string, update func(*Stats)) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	s, ok := c.stats[key]
+	if !ok {
+		s = &Stats{}
+		c.stats[key] = s
+	}
+	update(s)
+}
+
+// Stats returns lookup counts per key since the cache was created.
+func (c *Cache) Stats() map[string]Stats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	out := make(map[string]Stats, len(c.stats))
+	for k, s := range c.stats {
+		out[k] = *s
+	}
+	return out
+}
+
 // Set stores a value with a TTL.
 func (c *Cache) Set(key string, data any, ttl time.Duration) {
 	c.mu.Lock()
@@ -111,12 +151,38 @@ func (c *Cache) LoadFromDisk(key string, target any) bool {
 	if c.diskDir == "" {
 		return false
 	}
-	b, err := os.ReadFile(c.diskPath(key))
-	if err != nil {
+	ok := c.loadFromDisk(key, target)
+	c.record(key, func(s *Stats) {
+		if ok {
+			s.DiskHits++
+		} else {
+			s.DiskMisses++
+		}
+	})
+	return ok
+}
+
+// LoadStaleFromDisk is LoadFromDisk for a last resort: it loads the value
+// however long ago it expired, and leaves the file in place.
+func (c *Cache) LoadStaleFromDisk(key string, target any) bool {
+	if c.diskDir == "" {
 		return false
 	}
-	var de diskEntry
-	if err := json.Unmarshal(b, &de); err != nil {
+	de, ok := c.readDisk(key)
+	ok = ok && json.Unmarshal(de.Data, target) == nil
+	c.record(key, func(s *Stats) {
+		if ok {
+			s.DiskHits++
+		} else {
+			s.DiskMisses++
+		}
+	})
+	return ok
+}
+
+func (c *Cache) loadFromDisk(key string, target any) bool {
+	de, ok := c.readDisk(key)
+	if !ok {
 		return false
 	}
 	if time.Now().After(de.ExpiresAt) {
@@ -125,3 +191,12 @@ func (c *Cache) LoadFromDisk(key string, target any) bool {
 	}
 	return json.Unmarshal(de.Data, target) == nil
 }
+
+func (c *Cache) readDisk(key string) (diskEntry, bool) {
+	var de diskEntry
+	b, err := os.ReadFile(c.diskPath(key))
+	if err != nil {
+		return de, false
+	}
+	return de, json.Unmarshal(b, &de) == nil
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+)
+
+func runBreakeven(l *loader.Loader, cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("breakeven", flag.ContinueOnError)
+	league := fs.String("league", "", "league ID (required)")
+	gem := fs.String("gem", "", "base gem name for a specific roll")
+	color := fs.String("color", "", "gem color for a pool roll (r, g or b)")
+	fee := fs.Float64("fee", 0, "other chaos spent per attempt")
+	cost := fs.Float64("cost", 0, "base gem cost in chaos (default: poe.ninja price for --gem)")
+	target := fs.String("target", "", "transfigured gem to solve a target price for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	costSet := false
+	fs.Visit(func(f *flag.Flag) { costSet = costSet || f.Name == "cost" })
+	if *league == "" {
+		return errors.New("--league is required")
+	}
+	if (*gem == "") == (*color == "") {
+		return errors.New("exactly one of --gem or --color is required")
+	}
+
+	result, err := l.Process(*league, cfg.TopN, domain.NewExclusions(cfg.Exclude))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	if *gem != "" {
+		e, ok := result.Entry(*gem)
+		if !ok {
+			return fmt.Errorf("no transfigured variants found for %q", *gem)
+		}
+		base := e.BaseCost
+		if costSet {
+			base = *cost
+		}
+		total := base + *fee
+
+		fmt.Fprintf(w, "%s\t(%s, %d variants)\n", e.BaseName, e.Color.Label(), e.VariantCount)
+		fmt.Fprintf(w, "EV\t%s\n", domain.FormatChaos(e.EV))
+		fmt.Fprintf(w, "Base gem\t%s\n", domain.FormatChaos(base))
+		fmt.Fprintf(w, "Fee\t%s\n", domain.FormatChaos(*fee))
+		fmt.Fprintf(w, "Break-even base\t≤ %s\n", domain.FormatChaos(domain.BreakEvenBaseCost(e, *fee)))
+		fmt.Fprintf(w, "Net profit\t%s\n\n", domain.FormatChaosSigned(e.EV-total))
+
+		fmt.Fprintf(w, "Variant\tPrice\tBreak-even price\n")
+		for _, v := range e.Rollable() {
+			if *target != "" && !strings.EqualFold(v.Name, *target) {
+				continue
+			}
+			tp, err := domain.VariantTargetPrice(e, v.Name, total)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, domain.FormatChaos(v.SellPrice), domain.FormatChaos(tp))
+		}
+		return nil
+	}
+
+	gc, err := domain.ParseColor(*color)
+	if err != nil {
+		return err
+	}
+	stats := result.ColorStats[gc]
+	base := *cost
+	total := base + *fee
+
+	fmt.Fprintf(w, "%s pool\t(%d gems, best of %d)\n", gc.Label(), stats.PoolSize, domain.FontDraws)
+	fmt.Fprintf(w, "Pool EV\t%s\n", domain.FormatChaos(stats.PoolEV))
+	fmt.Fprintf(w, "Base gem\t%s\n", domain.FormatChaos(base))
+	fmt.Fprintf(w, "Fee\t%s\n", domain.FormatChaos(*fee))
+	fmt.Fprintf(w, "Break-even base\t≤ %s\n", domain.FormatChaos(domain.PoolBreakEvenCost(stats, *fee)))
+	fmt.Fprintf(w, "Net profit\t%s\n", domain.FormatChaosSigned(stats.PoolEV-total))
+
+	if *target != "" {
+		tp, err := domain.PoolTargetPrice(result, gc, *target, total)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n%s\tmust reach %s to cover %s\n",
+			*target, domain.FormatChaos(tp), domain.FormatChaos(total))
+	}
+	return nil
+}
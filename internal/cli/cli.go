@@ -0,0 +1,58 @@
+// Package cli implements gemcheck's headless subcommands.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+)
+
+// Usage is the top-level help text.
+const Usage = `Usage: gemcheck [global flags] [command] [flags]
+
+Without a command, gemcheck starts the interactive TUI.
+
+Commands:
+  breakeven   Solve for break-even base cost and variant target prices
+  ev          Print gem and pool EV as JSON
+  export      Write gem tables as CSV, TSV or Markdown
+  lookup      Show where a transfigured gem comes from and the odds of rolling it
+  watch       Poll prices and print alerts when rules fire
+  serve       Serve EV data as a JSON API
+  help        Show this help
+
+Run 'gemcheck <command> -h' for command flags and 'gemcheck -h' for
+global flags.
+`
+
+// Run executes the subcommand named by args[0] with the remaining args.
+// A -h flag prints the command's usage and returns nil.
+func Run(l *loader.Loader, cfg config.Config, args []string, stdout io.Writer) error {
+	var err error
+	switch args[0] {
+	case "breakeven":
+		err = runBreakeven(l, cfg, args[1:], stdout)
+	case "ev":
+		err = runEV(l, cfg, args[1:], stdout)
+	case "export":
+		err = runExport(l, cfg, args[1:], stdout)
+	case "lookup":
+		err = runLookup(l, cfg, args[1:], stdout)
+	case "watch":
+		err = runWatch(l, cfg, args[1:], stdout)
+	case "serve":
+		err = runServe(l, cfg, args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, Usage)
+	default:
+		err = fmt.Errorf("unknown command %q (run 'gemcheck help')", args[0])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/export"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+)
+
+func runEV(l *loader.Loader, cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("ev", flag.ContinueOnError)
+	league := fs.String("league", "", "league ID (required)")
+	color := fs.String("color", "", "only include gems and the pool of this color (r, g or b)")
+	top := fs.Int("top", cfg.TopN, "bingo gems per pool")
+	compact := fs.Bool("compact", false, "print JSON on one line")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *league == "" {
+		return errors.New("--league is required")
+	}
+
+	result, err := l.Process(*league, *top, domain.NewExclusions(cfg.Exclude))
+	if err != nil {
+		return err
+	}
+	out := export.NewResult(*league, time.Now(), result)
+
+	if *color != "" {
+		gc, err := domain.ParseColor(*color)
+		if err != nil {
+			return err
+		}
+		out = out.ForColor(gc)
+	}
+	return export.WriteJSON(stdout, out, *compact)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/export"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+	"github.com/ovestokke/gemcheck-tui/internal/score"
+)
+
+func runExport(l *loader.Loader, cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	league := fs.String("league", "", "league ID (required)")
+	format := fs.String("format", cfg.Export.Format, "csv, tsv or md")
+	table := fs.String("table", "gems", "table to export: "+strings.Join(export.Tables, ", "))
+	color := fs.String("color", "", "only include gems of this color (r, g or b)")
+	rank := fs.String("rank", "", "ranking strategy name for the gems table (default: EV)")
+	out := fs.String("out", "", "output file, or a directory for a timestamped file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *league == "" {
+		return errors.New("--league is required")
+	}
+	f, err := export.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	result, err := l.Process(*league, cfg.TopN, domain.NewExclusions(cfg.Exclude))
+	if err != nil {
+		return err
+	}
+
+	gems := result.GemPicks
+	label := "all"
+	if *color != "" {
+		gc, err := domain.ParseColor(*color)
+		if err != nil {
+			return err
+		}
+		gems = gems[:0:0]
+		for _, e := range result.GemPicks {
+			if e.Color == gc {
+				gems = append(gems, e)
+			}
+		}
+		label = strings.ToLower(gc.Label())
+	}
+
+	var t export.Table
+	if *rank != "" {
+		if *table != "gems" {
+			return errors.New("--rank only applies to --table gems")
+		}
+		s, err := findStrategy(cfg, *rank)
+		if err != nil {
+			return err
+		}
+		t = export.GemsTable(domain.RankGems(gems, s), &s)
+	} else if t, err = export.NewTable(*table, result, gems); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return t.Write(stdout, f)
+	}
+	if info, err := os.Stat(*out); err == nil && info.IsDir() {
+		path, err := export.WriteFile(*out, *league, *table+"-"+label, f, t, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, path)
+		return nil
+	}
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(file, f); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// findStrategy looks up a built-in or configured ranking strategy by name,
+// case-insensitively.
+func findStrategy(cfg config.Config, name string) (domain.RankStrategy, error) {
+	custom, errs := score.Strategies(cfg.Scores)
+	for _, s := range append(append([]domain.RankStrategy{}, domain.RankStrategies...), custom...) {
+		if strings.EqualFold(s.Name, name) {
+			if err := errs[s.Name]; err != nil {
+				return s, fmt.Errorf("score %q: %w", s.Name, err)
+			}
+			return s, nil
+		}
+	}
+	return domain.RankStrategy{}, fmt.Errorf("unknown ranking strategy %q", name)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+)
+
+func runLookup(l *loader.Loader, cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("lookup", flag.ContinueOnError)
+	league := fs.String("league", "", "league ID (required)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gemcheck lookup --league ID <transfigured gem name>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *league == "" {
+		return errors.New("--league is required")
+	}
+	name := strings.Join(fs.Args(), " ")
+	if name == "" {
+		return errors.New("a transfigured gem name is required")
+	}
+
+	result, err := l.Process(*league, cfg.TopN, domain.NewExclusions(cfg.Exclude))
+	if err != nil {
+		return err
+	}
+	lk, ok := domain.LookupVariant(result, name)
+	if !ok {
+		return fmt.Errorf("no transfigured gem called %q", name)
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	v := lk.Variant
+	fmt.Fprintf(w, "%s\t(%s pool, from %s)\n", v.Name, lk.Color.Label(), lk.BaseName)
+	if v.Listed {
+		fmt.Fprintf(w, "Price\t%s\t(%d listed)\n", domain.FormatChaos(v.SellPrice), v.Count)
+	} else {
+		fmt.Fprintf(w, "Price\tunlisted\n")
+	}
+	if v.Excluded {
+		fmt.Fprintln(w, "\nExcluded from font rolls")
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nRoll\tChance")
+	for _, t := range domain.LookupTargets {
+		fmt.Fprintf(w, "\t%.0f%% in", t*100)
+	}
+	fmt.Fprintln(w)
+	for _, row := range []struct {
+		label string
+		prob  float64
+	}{
+		{fmt.Sprintf("Transfigure %s (1 of %d)", lk.BaseName, lk.Variants), lk.SpecificProb},
+		{fmt.Sprintf("%s pool (%d of %d)", lk.Color.Label(), domain.FontDraws, lk.PoolSize), lk.PoolProb},
+	} {
+		fmt.Fprintf(w, "%s\t%s", row.label, domain.FormatPct(row.prob))
+		for _, t := range domain.LookupTargets {
+			fmt.Fprintf(w, "\t%d", domain.AttemptsFor(row.prob, t))
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
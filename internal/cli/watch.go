@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/alert"
+	"github.com/ovestokke/gemcheck-tui/internal/config"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+	"github.com/ovestokke/gemcheck-tui/internal/metrics"
+)
+
+func runWatch(l *loader.Loader, cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	league := fs.String("league", "", "league ID (required)")
+	interval := fs.Duration("interval", l.TTL().Prices, "time between polls (at least the price cache TTL)")
+	once := fs.Bool("once", false, "check the rules once and exit")
+	dryRun := fs.Bool("dry-run", false, "print webhook payloads instead of sending them")
+	metricsAddr := fs.String("metrics-addr", "", "serve Prometheus metrics at this address's /metrics")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *league == "" {
+		return errors.New("--league is required")
+	}
+	if len(cfg.Alerts) == 0 {
+		return errors.New("no alerts configured; add rules under \"alerts\" in the config file")
+	}
+	ev, err := alert.NewEvaluator(cfg.Alerts)
+	if err != nil {
+		return err
+	}
+	var notifier *alert.Notifier
+	if len(cfg.Webhooks) > 0 {
+		if notifier, err = alert.NewNotifier(cfg.Webhooks, cfg.Alerts); err != nil {
+			return err
+		}
+		notifier.DryRun, notifier.Out = *dryRun, stdout
+	}
+	if ttl := l.TTL().Prices; *interval < ttl {
+		// Polling faster would only re-read cached prices
+		*interval = ttl
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *metricsAddr != "" {
+		l.Metrics = metrics.New(l.Cache)
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", l.Metrics)
+		srv := &http.Server{Addr: *metricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintf(stdout, "metrics server: %v\n", err)
+			}
+		}()
+		defer srv.Close()
+	}
+
+	excluded := domain.NewExclusions(cfg.Exclude)
+	if *once {
+		// A single check has nothing to compare with but the last fetch
+		if err := primeFromHistory(l, ev, *league, cfg.TopN, excluded); err != nil && comparesFetches(cfg.Alerts) {
+			fmt.Fprintf(stdout, "price_move and bingo_new rules can't fire: %v\n", err)
+		}
+	} else {
+		fmt.Fprintf(stdout, "Watching %s: %d rules every %s\n", *league, len(cfg.Alerts), *interval)
+	}
+	for {
+		now := time.Now()
+		stamp := now.Format("2006-01-02 15:04:05")
+		result, err := l.Process(*league, cfg.TopN, excluded)
+		if err != nil {
+			if *once {
+				return err
+			}
+			// Keep watching through upstream hiccups
+			fmt.Fprintf(stdout, "%s fetch failed: %v\n", stamp, err)
+		} else {
+			alerts := ev.Evaluate(result, now)
+			for _, a := range alerts {
+				fmt.Fprintf(stdout, "%s [%s] %s\n", stamp, a.Rule, a.Message)
+			}
+			if notifier != nil && len(alerts) > 0 {
+				if err := notifier.Notify(ctx, alerts); err != nil {
+					fmt.Fprintf(stdout, "%s notify failed: %v\n", stamp, err)
+				}
+			}
+		}
+		if *once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// primeFromHistory sets ev's baselines from the league's last recorded fetch.
+func primeFromHistory(l *loader.Loader, ev *alert.Evaluator, league string, topN int, excluded domain.Exclusions) error {
+	if l.History == nil {
+		return errors.New("price history is disabled")
+	}
+	snaps, err := l.History.Snapshots(league)
+	if err != nil {
+		return err
+	}
+	if len(snaps) == 0 {
+		return fmt.Errorf("no price history for %s yet", league)
+	}
+	wiki, err := l.Wiki()
+	if err != nil {
+		return err
+	}
+	last := snaps[len(snaps)-1]
+	ev.Prime(domain.ProcessGems(*wiki, last.GemPrices(), topN, excluded), last.Time)
+	return nil
+}
+
+// comparesFetches reports whether any rule needs an earlier fetch to fire.
+func comparesFetches(rules []alert.Rule) bool {
+	for _, r := range rules {
+		if r.Kind == alert.PriceMove || r.Kind == alert.BingoNew {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+// Package config loads user settings from $XDG_CONFIG_HOME/gemcheck.
+//
+// Settings are layered: built-in defaults, then the config file, then
+// GEMCHECK_* environment variables, then command-line flags.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/alert"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/duration"
+	"github.com/ovestokke/gemcheck-tui/internal/export"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+)
+
+// Duration is a time.Duration written as a string such as "5m".
+type Duration = duration.Duration
+
+// Config is the user's settings file.
+type Config struct {
+	// CacheDir holds cached wiki data and price history.
+	CacheDir string `json:"cache_dir,omitempty"`
+
+	TTL TTL `json:"ttl,omitempty"`
+
+	// TopN is the number of bingo gems kept per pool.
+	TopN int `json:"top_n,omitempty"`
+
+	PriceTiers PriceTiers `json:"price_tiers,omitempty"`
+
+	// Keys rebinds TUI actions, e.g. {"refresh": ["r", "f5"]}.
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Scores maps a name to a scoring expression (see package score).
+	Scores map[string]string `json:"scores,omitempty"`
+
+	// Exclude lists transfigured gems to leave out of the pool math.
+	Exclude []string `json:"exclude,omitempty"`
+
+	Export Export `json:"export,omitempty"`
+
+	// Filters are named gem table filters, picked with p in the filter bar.
+	Filters map[string]domain.GemFilter `json:"filters,omitempty"`
+
+	// Favorites are base gem names kept by the favorites filter.
+	Favorites []string `json:"favorites,omitempty"`
+
+	// Alerts are the rules `gemcheck watch` checks on every poll.
+	Alerts []alert.Rule `json:"alerts,omitempty"`
+
+	// Webhooks receive fired alerts.
+	Webhooks []alert.Webhook `json:"webhooks,omitempty"`
+}
+
+// TTL is how long each kind of upstream data stays cached.
+type TTL struct {
+	Leagues Duration `json:"leagues,omitempty"`
+	Wiki    Duration `json:"wiki,omitempty"`
+	Prices  Duration `json:"prices,omitempty"`
+}
+
+// Loader converts the TTLs for loader.Loader.
+func (t TTL) Loader() loader.TTLs {
+	return loader.TTLs{
+		Leagues: time.Duration(t.Leagues),
+		Wiki:    time.Duration(t.Wiki),
+		Prices:  time.Duration(t.Prices),
+	}
+}
+
+// PriceTiers are the chaos values from which prices are colored as high or
+// mid value.
+type PriceTiers struct {
+	High float64 `json:"high,omitempty"`
+	Mid  float64 `json:"mid,omitempty"`
+}
+
+// Export configures table exports.
+type Export struct {
+	// Format is csv, tsv or md.
+	Format string `json:"format,omitempty"`
+
+	// Dir is where the TUI writes exports.
+	Dir string `json:"dir,omitempty"`
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	ttl := loader.DefaultTTLs
+	return Config{
+		CacheDir: defaultCacheDir(),
+		TTL: TTL{
+			Leagues: Duration(ttl.Leagues),
+			Wiki:    Duration(ttl.Wiki),
+			Prices:  Duration(ttl.Prices),
+		},
+		TopN:       domain.DefaultTopN,
+		PriceTiers: PriceTiers{High: 50, Mid: 10},
+		Export:     Export{Format: string(export.CSV), Dir: "."},
+	}
+}
+
+// defaultCacheDir is $XDG_CACHE_HOME/gemcheck or ~/.cache/gemcheck, or empty
+// when neither can be found.
+func defaultCacheDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gemcheck")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cache", "gemcheck")
+}
+
+// Dir returns the config directory: $XDG_CONFIG_HOME/gemcheck, or
+// ~/.config/gemcheck when XDG_CONFIG_HOME is unset.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gemcheck"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gemcheck"), nil
+}
+
+// Path returns the location of config.json.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load reads the config file at path over the defaults. A missing file is
+// not an error and yields Default().
+func Load(path string) (Config, error) {
+	cfg := Default()
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate reports every invalid setting, one per line.
+func (c Config) Validate() error {
+	var errs []error
+	bad := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{field}, args...)...))
+	}
+
+	if c.CacheDir == "" {
+		bad("cache_dir", "not set and no home directory to default to")
+	}
+	for _, ttl := range []struct {
+		field string
+		d     Duration
+	}{{"ttl.leagues", c.TTL.Leagues}, {"ttl.wiki", c.TTL.Wiki}, {"ttl.prices", c.TTL.Prices}} {
+		if ttl.d <= 0 {
+			bad(ttl.field, "must be positive, got %s", time.Duration(ttl.d))
+		}
+	}
+	if c.TopN < 1 {
+		bad("top_n", "must be at least 1, got %d", c.TopN)
+	}
+	if c.PriceTiers.Mid < 0 || c.PriceTiers.High <= c.PriceTiers.Mid {
+		bad("price_tiers", "need 0 <= mid < high, got mid %g, high %g", c.PriceTiers.Mid, c.PriceTiers.High)
+	}
+	if _, err := export.ParseFormat(c.Export.Format); err != nil {
+		bad("export.format", "%v", err)
+	}
+	for _, name := range sortedNames(c.Filters) {
+		if f := c.Filters[name]; f.MinEV < 0 || f.MinListed < 0 {
+			bad("filters."+name, "min_ev and min_listed can't be negative")
+		}
+	}
+	// Strategies are picked by name, case-insensitively, so a score can't
+	// share one with a built-in
+	for _, name := range sortedNames(c.Scores) {
+		for _, s := range domain.RankStrategies {
+			if strings.EqualFold(name, s.Name) {
+				bad("scores."+name, "%q is a built-in ranking strategy", s.Name)
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for i, r := range c.Alerts {
+		if err := r.Validate(); err != nil {
+			bad(fmt.Sprintf("alerts[%d]", i), "%v", err)
+		} else if seen[strings.ToLower(r.Name)] {
+			bad(fmt.Sprintf("alerts[%d]", i), "duplicate name %q", r.Name)
+		}
+		seen[strings.ToLower(r.Name)] = true
+	}
+	for i, w := range c.Webhooks {
+		if err := w.Validate(); err != nil {
+			bad(fmt.Sprintf("webhooks[%d]", i), "%v", err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaveFilter adds or replaces the named filter in the config file at path,
+// creating the file if needed. The file's other settings are kept as
+// written and in their order, not filled in with defaults, but the file is
+// re-indented and the filters are sorted by name. It is written to a
+// temporary file first, so path always holds a whole config.
+func SaveFilter(path, name string, f domain.GemFilter) error {
+	var fields settings
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	filters := make(map[string]domain.GemFilter)
+	if raw, ok := fields.get("filters"); ok {
+		if err := json.Unmarshal(raw, &filters); err != nil {
+			return fmt.Errorf("%s: filters: %w", path, err)
+		}
+	}
+	filters[name] = f
+	raw, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	fields.set("filters", raw)
+
+	b, err = json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// settings is a JSON object's fields in the order they were written.
+type settings []setting
+
+type setting struct {
+	key   string
+	value json.RawMessage
+}
+
+func (s settings) get(key string) (json.RawMessage, bool) {
+	for _, f := range s {
+		if f.key == key {
+			return f.value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the value of key, or adds it last.
+func (s *settings) set(key string, value json.RawMessage) {
+	for i, f := range *s {
+		if f.key == key {
+			(*s)[i].value = value
+			return
+		}
+	}
+	*s = append(*s, setting{key, value})
+}
+
+func (s *settings) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return errors.New("want a JSON object")
+	}
+	*s = nil
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		s.set(t.(string), value)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func (s settings) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range s {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(f.value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"flag"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMergesDefaults(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"ttl": {"prices": "10m"}, "price_tiers": {"high": 100}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := Default()
+	if time.Duration(cfg.TTL.Prices) != 10*time.Minute || cfg.TTL.Wiki != def.TTL.Wiki {
+		t.Errorf("ttl = %+v", cfg.TTL)
+	}
+	if cfg.PriceTiers.High != 100 || cfg.PriceTiers.Mid != def.PriceTiers.Mid {
+		t.Errorf("price_tiers = %+v", cfg.PriceTiers)
+	}
+	if cfg.TopN != def.TopN || cfg.Export.Format != "csv" {
+		t.Errorf("defaults lost: top_n %d, export %+v", cfg.TopN, cfg.Export)
+	}
+
+	if cfg, err := Load(filepath.Join(t.TempDir(), "missing.json")); err != nil || cfg.TopN != def.TopN {
+		t.Errorf("missing file: %v, %+v", err, cfg)
+	}
+	if _, err := Load(writeConfig(t, `{"topn": 5}`)); err == nil {
+		t.Error("unknown field accepted")
+	}
+	if _, err := Load(writeConfig(t, `{"ttl": {"prices": 300}}`)); err == nil {
+		t.Error("numeric duration accepted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.TTL.Prices = 0
+	cfg.TopN = 0
+	cfg.PriceTiers = PriceTiers{High: 5, Mid: 10}
+	cfg.Export.Format = "xlsx"
+	cfg.Filters = map[string]domain.GemFilter{"ok": {MinEV: 50}, "neg": {MinListed: -1}}
+	cfg.Scores = map[string]string{"liquid": "ev", "ev": "ev * 2"}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, field := range []string{"ttl.prices:", "top_n:", "price_tiers:", "export.format:", "filters.neg:", "scores.ev:"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error doesn't mention %s:\n%v", field, err)
+		}
+	}
+	if n := strings.Count(err.Error(), "\n") + 1; n != 6 {
+		t.Errorf("expected 6 errors, got %d:\n%v", n, err)
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Errorf("defaults invalid: %v", err)
+	}
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	path := writeConfig(t, `{"top_n": 5, "ttl": {"prices": "10m", "wiki": "48h"}}`)
+	env := map[string]string{
+		EnvConfig:   path,
+		EnvTopN:     "7",
+		EnvPriceTTL: "15m",
+	}
+	getenv := func(k string) string { return env[k] }
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := NewFlags(fs)
+	if err := fs.Parse([]string{"--price-ttl", "20m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, got, err := Resolve(flags, getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != path {
+		t.Errorf("path = %q, want %q", got, path)
+	}
+	if cfg.TopN != 7 {
+		t.Errorf("top_n = %d, want env's 7", cfg.TopN)
+	}
+	if time.Duration(cfg.TTL.Prices) != 20*time.Minute {
+		t.Errorf("ttl.prices = %v, want flag's 20m", time.Duration(cfg.TTL.Prices))
+	}
+	if time.Duration(cfg.TTL.Wiki) != 48*time.Hour {
+		t.Errorf("ttl.wiki = %v, want file's 48h", time.Duration(cfg.TTL.Wiki))
+	}
+
+	env[EnvTopN] = "many"
+	if _, _, err := Resolve(flags, getenv); err == nil || !strings.Contains(err.Error(), EnvTopN) {
+		t.Errorf("bad env var: %v", err)
+	}
+	env[EnvTopN] = "0"
+	if _, _, err := Resolve(flags, getenv); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("invalid config error should name the file: %v", err)
+	}
+}
+
+func TestSaveFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gemcheck", "config.json")
+	if err := SaveFilter(path, "liquid", domain.GemFilter{MinListed: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other settings stay as written and in order, and existing filters are
+	// kept
+	written := `{"top_n": 5, "filters": {"liquid": {"min_listed": 3}}, "price_tiers": {"mid": 10, "high": 100}}`
+	if err := os.WriteFile(path, []byte(written), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveFilter(path, "big", domain.GemFilter{MinEV: 100, AllListed: true}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TopN != 5 {
+		t.Errorf("top_n = %d, want 5", cfg.TopN)
+	}
+	want := map[string]domain.GemFilter{"liquid": {MinListed: 3}, "big": {MinEV: 100, AllListed: true}}
+	if !maps.Equal(cfg.Filters, want) {
+		t.Errorf("filters = %v, want %v", cfg.Filters, want)
+	}
+	b, _ := os.ReadFile(path)
+	if strings.Contains(string(b), "cache_dir") {
+		t.Errorf("defaults written to the file:\n%s", b)
+	}
+	top, filters, tiers := strings.Index(string(b), `"top_n"`), strings.Index(string(b), `"filters"`),
+		strings.Index(string(b), `"price_tiers"`)
+	if !(top < filters && filters < tiers) {
+		t.Errorf("settings reordered:\n%s", b)
+	}
+	if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("temporary files left behind: %v", files)
+	}
+}
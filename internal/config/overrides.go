@@ -0,0 +1,120 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Environment variables that override the config file.
+const (
+	EnvConfig    = "GEMCHECK_CONFIG"
+	EnvCacheDir  = "GEMCHECK_CACHE_DIR"
+	EnvTopN      = "GEMCHECK_TOP_N"
+	EnvLeagueTTL = "GEMCHECK_LEAGUE_TTL"
+	EnvWikiTTL   = "GEMCHECK_WIKI_TTL"
+	EnvPriceTTL  = "GEMCHECK_PRICE_TTL"
+)
+
+// Flags are the global command-line overrides. Only flags given on the
+// command line override anything.
+type Flags struct {
+	fs        *flag.FlagSet
+	path      string
+	cacheDir  string
+	topN      int
+	leagueTTL time.Duration
+	wikiTTL   time.Duration
+	priceTTL  time.Duration
+}
+
+// NewFlags registers the global flags on fs.
+func NewFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.path, "config", "", "config file (default $XDG_CONFIG_HOME/gemcheck/config.json, env "+EnvConfig+")")
+	fs.StringVar(&f.cacheDir, "cache-dir", "", "cache and history directory (env "+EnvCacheDir+")")
+	fs.IntVar(&f.topN, "top-n", 0, "bingo gems kept per pool (env "+EnvTopN+")")
+	fs.DurationVar(&f.leagueTTL, "league-ttl", 0, "league list cache TTL (env "+EnvLeagueTTL+")")
+	fs.DurationVar(&f.wikiTTL, "wiki-ttl", 0, "wiki data cache TTL (env "+EnvWikiTTL+")")
+	fs.DurationVar(&f.priceTTL, "price-ttl", 0, "price cache TTL (env "+EnvPriceTTL+")")
+	return f
+}
+
+func (f *Flags) apply(c *Config) {
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "cache-dir":
+			c.CacheDir = f.cacheDir
+		case "top-n":
+			c.TopN = f.topN
+		case "league-ttl":
+			c.TTL.Leagues = Duration(f.leagueTTL)
+		case "wiki-ttl":
+			c.TTL.Wiki = Duration(f.wikiTTL)
+		case "price-ttl":
+			c.TTL.Prices = Duration(f.priceTTL)
+		}
+	})
+}
+
+// applyEnv applies the GEMCHECK_* variables that are set.
+func applyEnv(c *Config, getenv func(string) string) error {
+	if v := getenv(EnvCacheDir); v != "" {
+		c.CacheDir = v
+	}
+	if v := getenv(EnvTopN); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvTopN, err)
+		}
+		c.TopN = n
+	}
+	for name, d := range map[string]*Duration{
+		EnvLeagueTTL: &c.TTL.Leagues,
+		EnvWikiTTL:   &c.TTL.Wiki,
+		EnvPriceTTL:  &c.TTL.Prices,
+	} {
+		if v := getenv(name); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*d = Duration(parsed)
+		}
+	}
+	return nil
+}
+
+// Resolve loads and validates the effective config: defaults, then the
+// config file (from --config, GEMCHECK_CONFIG or Path), then environment
+// variables, then flags. flags may be nil. The returned path is the config
+// file that was read, or would have been.
+func Resolve(flags *Flags, getenv func(string) string) (Config, string, error) {
+	path := getenv(EnvConfig)
+	if flags != nil && flags.path != "" {
+		path = flags.path
+	}
+	if path == "" {
+		p, err := Path()
+		if err != nil {
+			return Default(), "", err
+		}
+		path = p
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		return cfg, path, err
+	}
+	if err := applyEnv(&cfg, getenv); err != nil {
+		return cfg, path, err
+	}
+	if flags != nil {
+		flags.apply(&cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, path, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return cfg, path, nil
+}
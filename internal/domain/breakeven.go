@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BreakEvenBaseCost returns the most a base gem can cost for a specific
+// transfigure of e to break even, after fee chaos of other per-attempt costs.
+func BreakEvenBaseCost(e GemEntry, fee float64) float64 {
+	return e.EV - fee
+}
+
+// PoolBreakEvenCost returns the most a base gem can cost for a color-pool
+// roll to break even, after fee chaos of other per-attempt costs.
+func PoolBreakEvenCost(stats ColorStats, fee float64) float64 {
+	return stats.PoolEV - fee
+}
+
+// VariantTargetPrice returns the price the named variant of e must reach for
+// the specific-roll EV to cover cost. A result below the variant's current
+// price means the farm already breaks even; it is never negative.
+func VariantTargetPrice(e GemEntry, name string, cost float64) (float64, error) {
+	idx := -1
+	var others float64
+	for i, v := range e.Variants {
+		if strings.EqualFold(v.Name, name) {
+			idx = i
+			continue
+		}
+		others += v.SellPrice * v.Prob
+	}
+	if idx < 0 {
+		return 0, fmt.Errorf("%q is not a variant of %s", name, e.BaseName)
+	}
+	if e.Variants[idx].Excluded {
+		return 0, fmt.Errorf("%q is excluded from font rolls", name)
+	}
+	prob := e.Variants[idx].Prob
+	if prob <= 0 {
+		return 0, fmt.Errorf("%q has zero roll probability", name)
+	}
+	return math.Max(0, (cost-others)/prob), nil
+}
+
+// PoolTargetPrice returns the price the named gem must reach for its color's
+// best-of-FontDraws pool EV to cover cost. Like VariantTargetPrice, the result
+// may be below the current price and is never negative.
+func PoolTargetPrice(result ProcessedResult, color GemColor, name string, cost float64) (float64, error) {
+	pool := colorPool(result, color)
+	idx := -1
+	prices := make([]float64, len(pool))
+	for i, g := range pool {
+		prices[i] = g.SellPrice
+		if strings.EqualFold(g.Name, name) {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return 0, fmt.Errorf("%q is not in the %s pool", name, color.Label())
+	}
+
+	evAt := func(x float64) float64 {
+		prices[idx] = x
+		return bestOfEV(prices, FontDraws)
+	}
+	if evAt(0) >= cost {
+		return 0, nil
+	}
+
+	// Pool EV is non-decreasing in any one price and at least x times the
+	// chance of drawing that gem, which bounds the search.
+	n := float64(len(prices))
+	hitProb := 1 - math.Pow((n-1)/n, FontDraws)
+	lo, hi := 0.0, cost/hitProb
+	for range 100 {
+		mid := (lo + hi) / 2
+		if evAt(mid) >= cost {
+			hi = mid
+		} else {
+			lo = mid
+		}
+		if hi-lo < 0.001 {
+			break
+		}
+	}
+	return hi, nil
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBreakEvenBaseCost(t *testing.T) {
+	wiki := WikiData{
+		TransfigGems: map[GemColor][]string{
+			Red: {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+		},
+	}
+	prices := []GemPrice{
+		{Name: "Boneshatter", ChaosValue: 10},
+		{Name: "Boneshatter of Carnage", ChaosValue: 100},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50},
+	}
+	result := ProcessGems(wiki, prices, 5, nil)
+	e := result.GemPicks[0]
+
+	if e.BaseCost != 10 {
+		t.Errorf("expected base cost=10, got %.2f", e.BaseCost)
+	}
+	if got := BreakEvenBaseCost(e, 5); math.Abs(got-70) > 0.01 {
+		t.Errorf("BreakEvenBaseCost = %.2f, want 70", got)
+	}
+	if got := PoolBreakEvenCost(result.ColorStats[Red], 0); math.Abs(got-93.75) > 0.01 {
+		t.Errorf("PoolBreakEvenCost = %.2f, want 93.75", got)
+	}
+}
+
+func TestVariantTargetPrice(t *testing.T) {
+	e := GemEntry{
+		BaseName: "Boneshatter",
+		Variants: []GemVariantResult{
+			{Name: "Boneshatter of Carnage", SellPrice: 100, Prob: 0.5},
+			{Name: "Boneshatter of Complex Trauma", SellPrice: 50, Prob: 0.5},
+		},
+	}
+	tests := []struct {
+		name string
+		cost float64
+		want float64
+	}{
+		// EV = (x + 50) / 2 >= 120 -> x >= 190
+		{"boneshatter of carnage", 120, 190},
+		// Already covered by the other variant alone
+		{"Boneshatter of Carnage", 10, 0},
+	}
+	for _, tt := range tests {
+		got, err := VariantTargetPrice(e, tt.name, tt.cost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("VariantTargetPrice(%q, %g) = %.2f, want %g", tt.name, tt.cost, got, tt.want)
+		}
+	}
+
+	if _, err := VariantTargetPrice(e, "Boneshatter of Nothing", 10); err == nil {
+		t.Error("expected error for unknown variant")
+	}
+}
+
+func TestPoolTargetPrice(t *testing.T) {
+	wiki := WikiData{
+		TransfigGems: map[GemColor][]string{
+			Red: {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+		},
+	}
+	prices := []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 100},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50},
+	}
+	result := ProcessGems(wiki, prices, 5, nil)
+
+	tests := []struct {
+		cost float64
+		want float64
+	}{
+		// Raising Complex Trauma above Carnage: EV = x*0.875 + 100*0.125 >= 100 -> x = 100
+		{100, 100},
+		// Below Carnage: EV = 100*0.875 + x*0.125 >= 90 -> x = 20
+		{90, 20},
+	}
+	for _, tt := range tests {
+		got, err := PoolTargetPrice(result, Red, "Boneshatter of Complex Trauma", tt.cost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("PoolTargetPrice(%g) = %.3f, want %g", tt.cost, got, tt.want)
+		}
+	}
+
+	if _, err := PoolTargetPrice(result, Blue, "Boneshatter of Carnage", 10); err == nil {
+		t.Error("expected error for gem outside the pool")
+	}
+}
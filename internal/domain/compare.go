@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// GemComparison is one base gem's EV in two leagues.
+type GemComparison struct {
+	BaseName string
+	Color    GemColor
+	A, B     float64
+}
+
+// Ratio returns B's EV over A's, or 0 unless both are priced.
+func (c GemComparison) Ratio() float64 {
+	if c.A <= 0 || c.B <= 0 {
+		return 0
+	}
+	return c.B / c.A
+}
+
+// Divergence is how far apart the two EVs are as a factor, so that twice and
+// half as much score the same. Gems not priced in both leagues score 0.
+func (c GemComparison) Divergence() float64 {
+	if r := c.Ratio(); r > 0 {
+		return math.Abs(math.Log(r))
+	}
+	return 0
+}
+
+// LeagueComparison sets two leagues' results, processed with the same wiki
+// data, side by side.
+type LeagueComparison struct {
+	Pools map[GemColor][2]float64 // pool EV in A and B
+	Gems  []GemComparison
+}
+
+// CompareLeagues pairs up a and b by base gem. Gems are ordered by
+// divergence, biggest first, then those priced in only one league by EV.
+func CompareLeagues(a, b ProcessedResult) LeagueComparison {
+	c := LeagueComparison{Pools: make(map[GemColor][2]float64)}
+	for _, color := range AllColors {
+		c.Pools[color] = [2]float64{a.ColorStats[color].PoolEV, b.ColorStats[color].PoolEV}
+	}
+
+	byName := make(map[string]int)
+	add := func(e GemEntry) *GemComparison {
+		i, ok := byName[e.BaseName]
+		if !ok {
+			i = len(c.Gems)
+			byName[e.BaseName] = i
+			c.Gems = append(c.Gems, GemComparison{BaseName: e.BaseName, Color: e.Color})
+		}
+		return &c.Gems[i]
+	}
+	for _, e := range a.GemPicks {
+		add(e).A = e.EV
+	}
+	for _, e := range b.GemPicks {
+		add(e).B = e.EV
+	}
+
+	sort.Slice(c.Gems, func(i, j int) bool {
+		gi, gj := c.Gems[i], c.Gems[j]
+		if di, dj := gi.Divergence(), gj.Divergence(); di != dj {
+			return di > dj
+		}
+		if mi, mj := math.Max(gi.A, gi.B), math.Max(gj.A, gj.B); mi != mj {
+			return mi > mj
+		}
+		return gi.BaseName < gj.BaseName
+	})
+	return c
+}
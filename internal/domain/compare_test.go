@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareLeagues(t *testing.T) {
+	wiki := WikiData{
+		TransfigGems: map[GemColor][]string{
+			Red:  {"Boneshatter of Carnage", "Cleave of Rage", "Sunder of Earthbreaking"},
+			Blue: {"Arc of Surging"},
+		},
+	}
+	sc := ProcessGems(wiki, []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 100},
+		{Name: "Cleave of Rage", ChaosValue: 40},
+		{Name: "Arc of Surging", ChaosValue: 10},
+	}, 5, nil)
+	hc := ProcessGems(wiki, []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 50},
+		{Name: "Cleave of Rage", ChaosValue: 160},
+		{Name: "Sunder of Earthbreaking", ChaosValue: 30},
+		{Name: "Arc of Surging", ChaosValue: 11},
+	}, 5, nil)
+
+	c := CompareLeagues(sc, hc)
+
+	// Cleave is 4x (divergence ln 4) ahead of Boneshatter's 0.5x (ln 2) and
+	// Arc's 1.1x; Sunder, unpriced in A, comes last
+	var names []string
+	for _, g := range c.Gems {
+		names = append(names, g.BaseName)
+	}
+	want := []string{"Cleave", "Boneshatter", "Arc", "Sunder"}
+	if len(names) != len(want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("got %v, want %v", names, want)
+		}
+	}
+
+	if r := c.Gems[0].Ratio(); math.Abs(r-4) > 1e-9 {
+		t.Errorf("Cleave ratio = %v, want 4", r)
+	}
+	if s := c.Gems[3]; s.Ratio() != 0 || s.A != 0 || s.B != 30 || s.Color != Red {
+		t.Errorf("unexpected Sunder comparison: %+v", s)
+	}
+	if p := c.Pools[Red]; p[0] != sc.ColorStats[Red].PoolEV || p[1] != hc.ColorStats[Red].PoolEV {
+		t.Errorf("red pools = %v", p)
+	}
+}
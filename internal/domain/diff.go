@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// GemDelta describes how one gem entry changed between two results.
+type GemDelta struct {
+	BaseName string
+	Color    GemColor
+	OldEV    float64
+	NewEV    float64
+	OldRank  int // 1-based EV rank within its color, 0 if absent
+	NewRank  int
+	Listed   []string // variants that gained a listing
+	Delisted []string // variants that lost their listing
+}
+
+// EVChange returns the change in EV, new minus old.
+func (d GemDelta) EVChange() float64 { return d.NewEV - d.OldEV }
+
+// RankChange returns how many places the gem moved up its color's EV ranking.
+// New and removed gems report 0.
+func (d GemDelta) RankChange() int {
+	if d.OldRank == 0 || d.NewRank == 0 {
+		return 0
+	}
+	return d.OldRank - d.NewRank
+}
+
+// Added reports whether the gem only exists in the new result.
+func (d GemDelta) Added() bool { return d.OldRank == 0 }
+
+// Removed reports whether the gem only exists in the old result.
+func (d GemDelta) Removed() bool { return d.NewRank == 0 }
+
+// Changed reports whether anything about the gem differs.
+func (d GemDelta) Changed() bool {
+	return d.EVChange() != 0 || d.OldRank != d.NewRank || len(d.Listed) > 0 || len(d.Delisted) > 0
+}
+
+// DiffResults compares two results gem by gem and returns the entries that
+// changed, biggest absolute EV move first.
+func DiffResults(old, cur ProcessedResult) []GemDelta {
+	oldEntries, oldRanks := indexByBase(old)
+	newEntries, newRanks := indexByBase(cur)
+
+	names := make(map[string]bool)
+	for name := range oldEntries {
+		names[name] = true
+	}
+	for name := range newEntries {
+		names[name] = true
+	}
+
+	var deltas []GemDelta
+	for name := range names {
+		o, inOld := oldEntries[name]
+		n, inNew := newEntries[name]
+		d := GemDelta{
+			BaseName: name,
+			OldEV:    o.EV,
+			NewEV:    n.EV,
+			OldRank:  oldRanks[name],
+			NewRank:  newRanks[name],
+		}
+		if inNew {
+			d.Color = n.Color
+		} else {
+			d.Color = o.Color
+		}
+		if inOld && inNew {
+			d.Listed, d.Delisted = listingChanges(o, n)
+		}
+		if d.Changed() {
+			deltas = append(deltas, d)
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		ai, aj := math.Abs(deltas[i].EVChange()), math.Abs(deltas[j].EVChange())
+		if ai != aj {
+			return ai > aj
+		}
+		return deltas[i].BaseName < deltas[j].BaseName
+	})
+	return deltas
+}
+
+// PoolChanges returns the change in pool EV per color, new minus old.
+func PoolChanges(old, cur ProcessedResult) map[GemColor]float64 {
+	changes := make(map[GemColor]float64)
+	for _, c := range AllColors {
+		changes[c] = cur.ColorStats[c].PoolEV - old.ColorStats[c].PoolEV
+	}
+	return changes
+}
+
+// indexByBase maps base names to entries and to their EV rank within color.
+func indexByBase(r ProcessedResult) (map[string]GemEntry, map[string]int) {
+	entries := make(map[string]GemEntry, len(r.GemPicks))
+	ranks := make(map[string]int, len(r.GemPicks))
+	byEV := RankGems(r.GemPicks, RankStrategies[0])
+	perColor := make(map[GemColor]int)
+	for _, e := range byEV {
+		perColor[e.Color]++
+		entries[e.BaseName] = e
+		ranks[e.BaseName] = perColor[e.Color]
+	}
+	return entries, ranks
+}
+
+func listingChanges(old, cur GemEntry) (listed, delisted []string) {
+	wasListed := make(map[string]bool)
+	for _, v := range old.Variants {
+		wasListed[v.Name] = v.Listed
+	}
+	for _, v := range cur.Variants {
+		was, known := wasListed[v.Name]
+		switch {
+		case v.Listed && (!known || !was):
+			listed = append(listed, v.Name)
+		case !v.Listed && known && was:
+			delisted = append(delisted, v.Name)
+		}
+	}
+	return listed, delisted
+}
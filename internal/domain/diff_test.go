@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	wiki := WikiData{
+		TransfigGems: map[GemColor][]string{
+			Red:   {"Boneshatter of Carnage", "Boneshatter of Complex Trauma", "Cleave of Rage"},
+			Green: {},
+			Blue:  {},
+		},
+	}
+	before := ProcessGems(wiki, []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 100},
+		{Name: "Cleave of Rage", ChaosValue: 40},
+	}, 5, nil)
+	after := ProcessGems(wiki, []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 20},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 10},
+		{Name: "Cleave of Rage", ChaosValue: 40},
+	}, 5, nil)
+
+	deltas := DiffResults(before, after)
+	if len(deltas) != 2 {
+		t.Fatalf("expected 2 changed gems, got %d: %+v", len(deltas), deltas)
+	}
+
+	// Boneshatter: 50 -> 15, rank 1 -> 2, Complex Trauma newly listed
+	b := deltas[0]
+	if b.BaseName != "Boneshatter" {
+		t.Fatalf("expected Boneshatter as biggest mover, got %q", b.BaseName)
+	}
+	if math.Abs(b.EVChange()+35) > 0.01 {
+		t.Errorf("expected EV change -35, got %.2f", b.EVChange())
+	}
+	if b.RankChange() != -1 {
+		t.Errorf("expected rank change -1, got %d", b.RankChange())
+	}
+	if len(b.Listed) != 1 || b.Listed[0] != "Boneshatter of Complex Trauma" {
+		t.Errorf("expected Complex Trauma newly listed, got %v", b.Listed)
+	}
+
+	// Cleave keeps its EV but moves up a place
+	c := deltas[1]
+	if c.BaseName != "Cleave" || c.RankChange() != 1 || c.EVChange() != 0 {
+		t.Errorf("unexpected Cleave delta: %+v", c)
+	}
+
+	if got := PoolChanges(before, after)[Red]; got >= 0 {
+		t.Errorf("expected red pool EV to fall, got %+.2f", got)
+	}
+}
@@ -8,8 +8,13 @@ import (
 
 const FontDraws = 3
 
+// DefaultTopN is the number of bingo gems kept per color.
+const DefaultTopN = 10
+
 // ProcessGems calculates EV statistics from wiki gem data and ninja prices.
-func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
+// Excluded gems are kept as zero-probability variants on their GemEntry but
+// left out of every EV, pool size and bingo list.
+func ProcessGems(wiki WikiData, prices []GemPrice, topN int, excluded Exclusions) ProcessedResult {
 	// Build price lookup: name -> cheapest non-corrupted entry
 	priceMap := make(map[string]GemPrice)
 	totalLines := 0
@@ -47,13 +52,21 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 				Count:     count,
 				Icon:      icon,
 				Listed:    listed,
+				Excluded:  excluded.Has(name),
 			})
 		}
 
 		for baseName, variants := range byBase {
-			n := len(variants)
+			n := 0
+			for _, v := range variants {
+				if !v.Excluded {
+					n++
+				}
+			}
 			for i := range variants {
-				variants[i].Prob = 1.0 / float64(n)
+				if !variants[i].Excluded {
+					variants[i].Prob = 1.0 / float64(n)
+				}
 			}
 			sort.Slice(variants, func(i, j int) bool {
 				return variants[i].SellPrice > variants[j].SellPrice
@@ -61,9 +74,9 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 
 			var ev float64
 			for _, v := range variants {
-				ev += v.SellPrice
+				ev += v.SellPrice * v.Prob
 			}
-			ev /= float64(n)
+			base, basePriced := priceMap[baseName]
 
 			gemEntries = append(gemEntries, GemEntry{
 				BaseName:     baseName,
@@ -71,6 +84,8 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 				Variants:     variants,
 				EV:           ev,
 				VariantCount: n,
+				BaseCost:     base.ChaosValue,
+				BasePriced:   basePriced,
 			})
 		}
 	}
@@ -85,7 +100,12 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 	totalTransfig := 0
 
 	for _, c := range AllColors {
-		names := wiki.TransfigGems[c]
+		var names []string
+		for _, name := range wiki.TransfigGems[c] {
+			if !excluded.Has(name) {
+				names = append(names, name)
+			}
+		}
 		n := len(names)
 		totalTransfig += n
 
@@ -111,12 +131,10 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 			return pool[i].sellPrice > pool[j].sellPrice
 		})
 
-		// EV of best-of-3: P(gem at sorted-index i is max) = ((n-i)/n)^k - ((n-i-1)/n)^k
+		// EV of best-of-3
 		var poolEV float64
 		for i, g := range pool {
-			pBest := math.Pow(float64(n-i)/float64(n), FontDraws) -
-				math.Pow(float64(n-i-1)/float64(n), FontDraws)
-			poolEV += g.sellPrice * pBest
+			poolEV += g.sellPrice * bestOfProb(i, n, FontDraws)
 		}
 
 		// Bingo: top gems with prices
@@ -143,6 +161,7 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 			PoolSize: n,
 			PoolEV:   poolEV,
 			Bingo:    bingo,
+			Excluded: len(wiki.TransfigGems[c]) - n,
 		}
 	}
 
@@ -154,6 +173,31 @@ func ProcessGems(wiki WikiData, prices []GemPrice, topN int) ProcessedResult {
 	}
 }
 
+// bestOfProb returns the probability that the gem at sorted-descending index i
+// is the best of k independent draws from a pool of n:
+// P = ((n-i)/n)^k - ((n-i-1)/n)^k
+func bestOfProb(i, n, k int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	return math.Pow(float64(n-i)/float64(n), float64(k)) -
+		math.Pow(float64(n-i-1)/float64(n), float64(k))
+}
+
+// bestOfEV returns the expected best price of k draws from a pool of prices.
+// The input slice is not modified.
+func bestOfEV(prices []float64, k int) float64 {
+	sorted := make([]float64, len(prices))
+	copy(sorted, prices)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	var ev float64
+	for i, p := range sorted {
+		ev += p * bestOfProb(i, len(sorted), k)
+	}
+	return ev
+}
+
 // extractBaseName extracts the base gem name from a transfigured gem name.
 // e.g. "Boneshatter of Carnage" -> "Boneshatter"
 func extractBaseName(name string) string {
@@ -170,6 +214,9 @@ func FormatChaos(v float64) string {
 	if v == 0 {
 		return "—"
 	}
+	if v < 0 {
+		return "-" + FormatChaos(-v)
+	}
 	if v >= 1000 {
 		return fmt.Sprintf("%.1fk c", v/1000)
 	}
@@ -179,6 +226,14 @@ func FormatChaos(v float64) string {
 	return fmt.Sprintf("%.1fc", v)
 }
 
+// FormatChaosSigned formats a chaos delta with an explicit sign.
+func FormatChaosSigned(v float64) string {
+	if v > 0 {
+		return "+" + FormatChaos(v)
+	}
+	return FormatChaos(v)
+}
+
 // FormatPct formats a probability as a percentage.
 func FormatPct(p float64) string {
 	return fmt.Sprintf("%.1f%%", p*100)
@@ -42,7 +42,7 @@ func TestProcessGems_EVCalculation(t *testing.T) {
 		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50, Count: 3},
 	}
 
-	result := ProcessGems(wiki, prices, 5)
+	result := ProcessGems(wiki, prices, 5, nil)
 
 	// Specific roll EV for Boneshatter: (100 + 50) / 2 = 75
 	if len(result.GemPicks) != 1 {
@@ -85,7 +85,7 @@ func TestProcessGems_CorruptedFiltered(t *testing.T) {
 		{Name: "Boneshatter of Carnage", ChaosValue: 100, Corrupted: false},
 	}
 
-	result := ProcessGems(wiki, prices, 5)
+	result := ProcessGems(wiki, prices, 5, nil)
 	if len(result.GemPicks) != 1 {
 		t.Fatalf("expected 1 gem pick, got %d", len(result.GemPicks))
 	}
@@ -103,6 +103,7 @@ func TestFormatChaos(t *testing.T) {
 		{5.5, "5.5c"},
 		{150, "150c"},
 		{1500, "1.5k c"},
+		{-12.5, "-12.5c"},
 	}
 	for _, tt := range tests {
 		got := FormatChaos(tt.input)
@@ -121,3 +122,50 @@ func TestHitProbability(t *testing.T) {
 		t.Errorf("expected ~9.1%%, got %.4f", expected)
 	}
 }
+
+func TestProcessGems_Exclusions(t *testing.T) {
+	wiki := WikiData{
+		TransfigGems: map[GemColor][]string{
+			Red:   {"Boneshatter of Carnage", "Boneshatter of Complex Trauma", "Cleave of Rage"},
+			Green: {},
+			Blue:  {},
+		},
+	}
+	prices := []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 100},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50},
+		{Name: "Cleave of Rage", ChaosValue: 10},
+	}
+
+	result := ProcessGems(wiki, prices, 5, NewExclusions([]string{"boneshatter of carnage"}))
+
+	e, ok := result.Entry("Boneshatter")
+	if !ok {
+		t.Fatal("Boneshatter entry missing")
+	}
+	if len(e.Variants) != 2 || e.VariantCount != 1 {
+		t.Errorf("expected 2 variants with 1 rollable, got %d/%d", len(e.Variants), e.VariantCount)
+	}
+	if math.Abs(e.EV-50) > 0.01 {
+		t.Errorf("expected EV=50 without the excluded variant, got %.2f", e.EV)
+	}
+	for _, v := range e.Variants {
+		if v.Name == "Boneshatter of Carnage" && (!v.Excluded || v.Prob != 0) {
+			t.Errorf("excluded variant: Excluded=%v Prob=%.2f", v.Excluded, v.Prob)
+		}
+	}
+
+	// Pool of [50, 10]: 50*0.875 + 10*0.125 = 45
+	red := result.ColorStats[Red]
+	if red.PoolSize != 2 || red.Excluded != 1 {
+		t.Errorf("expected pool size 2 with 1 excluded, got %d/%d", red.PoolSize, red.Excluded)
+	}
+	if math.Abs(red.PoolEV-45) > 0.01 {
+		t.Errorf("expected pool EV=45, got %.2f", red.PoolEV)
+	}
+	for _, b := range red.Bingo {
+		if b.Name == "Boneshatter of Carnage" {
+			t.Error("excluded gem in bingo list")
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "strings"
+
+// Exclusions is a case-insensitive set of transfigured gem names left out of
+// the roll and pool math, such as gems listed on the wiki that can't be
+// rolled at the Divine Font.
+type Exclusions map[string]bool
+
+// NewExclusions builds the set from the user's exclude list.
+func NewExclusions(user []string) Exclusions {
+	ex := make(Exclusions, len(user))
+	for _, name := range user {
+		ex[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return ex
+}
+
+// Has reports whether a gem is excluded. A nil Exclusions excludes nothing.
+func (ex Exclusions) Has(name string) bool {
+	return ex[strings.ToLower(name)]
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "fmt"
+
+// GemFilter narrows a list of gems. The zero value keeps every gem.
+type GemFilter struct {
+	// MinEV hides gems with a lower EV.
+	MinEV float64 `json:"min_ev,omitempty"`
+
+	// MinListed hides gems with fewer variants listed on poe.ninja.
+	MinListed int `json:"min_listed,omitempty"`
+
+	// AllListed hides gems with any unlisted variant, whose EV counts that
+	// variant as worthless.
+	AllListed bool `json:"all_listed,omitempty"`
+
+	// Favorites keeps only favorite gems.
+	Favorites bool `json:"favorites,omitempty"`
+}
+
+// IsZero reports whether the filter keeps every gem.
+func (f GemFilter) IsZero() bool { return f == GemFilter{} }
+
+// Match reports whether e passes the filter. favorites holds favorite base
+// names.
+func (f GemFilter) Match(e GemEntry, favorites map[string]bool) bool {
+	switch {
+	case e.EV < f.MinEV:
+		return false
+	case e.ListedCount() < f.MinListed:
+		return false
+	case f.AllListed && e.ListedCount() < len(e.Rollable()):
+		return false
+	case f.Favorites && !favorites[e.BaseName]:
+		return false
+	}
+	return true
+}
+
+// Chips describes each active rule in a few words, e.g. "EV ≥ 50.0c".
+func (f GemFilter) Chips() []string {
+	var chips []string
+	if f.MinEV > 0 {
+		chips = append(chips, "EV ≥ "+FormatChaos(f.MinEV))
+	}
+	if f.MinListed > 0 {
+		chips = append(chips, fmt.Sprintf("≥ %d listed", f.MinListed))
+	}
+	if f.AllListed {
+		chips = append(chips, "all listed")
+	}
+	if f.Favorites {
+		chips = append(chips, "favorites")
+	}
+	return chips
+}
+
+// FilterGems returns the entries that pass f, in order.
+func FilterGems(entries []GemEntry, f GemFilter, favorites map[string]bool) []GemEntry {
+	if f.IsZero() {
+		return entries
+	}
+	var out []GemEntry
+	for _, e := range entries {
+		if f.Match(e, favorites) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterGems(t *testing.T) {
+	entries := []GemEntry{
+		{BaseName: "Patchy", EV: 80, Variants: []GemVariantResult{
+			{Name: "Patchy of A", Listed: true}, {Name: "Patchy of B"}, {Name: "Patchy of C", Listed: true},
+		}},
+		{BaseName: "Cheap", EV: 5, Variants: []GemVariantResult{
+			{Name: "Cheap of A", Listed: true}, {Name: "Cheap of B", Listed: true},
+		}},
+		{BaseName: "Solid", EV: 60, Variants: []GemVariantResult{
+			{Name: "Solid of A", Listed: true}, {Name: "Solid of B", Listed: true},
+			{Name: "Solid of Excluded", Excluded: true},
+		}},
+	}
+	favorites := map[string]bool{"Cheap": true, "Patchy": true}
+
+	for _, tc := range []struct {
+		name   string
+		filter GemFilter
+		want   []string
+	}{
+		{"zero", GemFilter{}, []string{"Patchy", "Cheap", "Solid"}},
+		{"min EV", GemFilter{MinEV: 50}, []string{"Patchy", "Solid"}},
+		{"min listed", GemFilter{MinListed: 2}, []string{"Patchy", "Cheap", "Solid"}},
+		{"min listed high", GemFilter{MinListed: 3}, nil},
+		{"all listed ignores excluded", GemFilter{AllListed: true}, []string{"Cheap", "Solid"}},
+		{"favorites", GemFilter{Favorites: true}, []string{"Patchy", "Cheap"}},
+		{"combined", GemFilter{MinEV: 50, AllListed: true}, []string{"Solid"}},
+	} {
+		var got []string
+		for _, e := range FilterGems(entries, tc.filter, favorites) {
+			got = append(got, e.BaseName)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestGemFilterChips(t *testing.T) {
+	if chips := (GemFilter{}).Chips(); len(chips) != 0 {
+		t.Errorf("zero filter chips = %v, want none", chips)
+	}
+	got := GemFilter{MinEV: 50, MinListed: 3, AllListed: true, Favorites: true}.Chips()
+	want := []string{"EV ≥ 50.0c", "≥ 3 listed", "all listed", "favorites"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Chips() = %v, want %v", got, want)
+	}
+}
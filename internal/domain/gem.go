@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // GemColor represents the attribute color of a gem.
 type GemColor string
 
@@ -11,6 +17,19 @@ const (
 
 var AllColors = []GemColor{Red, Green, Blue}
 
+// ParseColor accepts a color code ("r") or label ("Red"), case-insensitively.
+func ParseColor(s string) (GemColor, error) {
+	switch strings.ToLower(s) {
+	case "r", "red", "str":
+		return Red, nil
+	case "g", "green", "dex":
+		return Green, nil
+	case "b", "blue", "int":
+		return Blue, nil
+	}
+	return "", fmt.Errorf("unknown gem color %q (want r, g or b)", s)
+}
+
 func (c GemColor) Label() string {
 	switch c {
 	case Red:
@@ -58,6 +77,7 @@ type GemVariantResult struct {
 	Count     int
 	Icon      string
 	Listed    bool
+	Excluded  bool // not rollable at the font; Prob is 0
 }
 
 // GemEntry represents a base gem and its transfigured variants with EV.
@@ -66,7 +86,27 @@ type GemEntry struct {
 	Color        GemColor
 	Variants     []GemVariantResult
 	EV           float64
-	VariantCount int
+	VariantCount int     // rollable variants, excluding Excluded ones
+	BaseCost     float64 // cheapest listing of the base gem, 0 if unlisted
+	BasePriced   bool    // whether poe.ninja lists the base gem
+}
+
+// Rollable returns the variants a transfigure can produce, i.e. all but the
+// excluded ones.
+func (e GemEntry) Rollable() []GemVariantResult {
+	vs := make([]GemVariantResult, 0, len(e.Variants))
+	for _, v := range e.Variants {
+		if !v.Excluded {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// NetProfit returns the EV of transfiguring this gem minus the base gem cost.
+// With an unpriced base gem that is just the EV; see BasePriced.
+func (e GemEntry) NetProfit() float64 {
+	return e.EV - e.BaseCost
 }
 
 // BingoGem is a top gem in the color pool with its hit probability.
@@ -84,6 +124,7 @@ type ColorStats struct {
 	PoolSize int
 	PoolEV   float64
 	Bingo    []BingoGem
+	Excluded int // wiki gems left out of the pool
 }
 
 // ProcessedResult holds all computed data ready for display.
@@ -94,10 +135,64 @@ type ProcessedResult struct {
 	TotalTransfig int
 }
 
+// Entry looks up a gem entry by base name, case-insensitively.
+func (r ProcessedResult) Entry(baseName string) (GemEntry, bool) {
+	for _, e := range r.GemPicks {
+		if strings.EqualFold(e.BaseName, baseName) {
+			return e, true
+		}
+	}
+	return GemEntry{}, false
+}
+
 // League represents a PoE league.
 type League struct {
-	ID   string
-	Text string
+	ID       string
+	Text     string
+	Realm    string    // pc, xbox or sony
+	StartAt  time.Time // zero if unknown
+	EndAt    time.Time // zero until an end is announced
+	Hardcore bool
+	SSF      bool // solo self-found, which has no market to price
+	Event    bool
+}
+
+// League kinds, in the order league selection groups them.
+const (
+	KindSoftcore = "Softcore"
+	KindHardcore = "Hardcore"
+	KindEvent    = "Event"
+	KindSSF      = "SSF"
+)
+
+// LeagueKinds lists the kinds in display order.
+var LeagueKinds = []string{KindSoftcore, KindHardcore, KindEvent, KindSSF}
+
+// Kind returns the group the league is listed under. SSF wins over the
+// others since it decides whether prices exist at all.
+func (l League) Kind() string {
+	switch {
+	case l.SSF:
+		return KindSSF
+	case l.Event:
+		return KindEvent
+	case l.Hardcore:
+		return KindHardcore
+	}
+	return KindSoftcore
+}
+
+// Day returns which day of the league now falls on, 1 on launch day, or 0
+// when the start is unknown or more than a year ago, as for Standard.
+func (l League) Day(now time.Time) int {
+	if l.StartAt.IsZero() || now.Before(l.StartAt) {
+		return 0
+	}
+	day := int(now.Sub(l.StartAt)/(24*time.Hour)) + 1
+	if day > 365 {
+		return 0
+	}
+	return day
 }
 
 // WikiData holds scraped gem data from poewiki.
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeague(t *testing.T) {
+	start := time.Date(2026, 7, 25, 20, 0, 0, 0, time.UTC)
+	challenge := League{ID: "Settlers", StartAt: start}
+
+	for _, tc := range []struct {
+		now  time.Time
+		want int
+	}{
+		{start.Add(-time.Hour), 0},
+		{start.Add(time.Hour), 1},
+		{start.Add(24 * time.Hour), 2},
+		{start.Add(400 * 24 * time.Hour), 0}, // permanent leagues have no day
+	} {
+		if got := challenge.Day(tc.now); got != tc.want {
+			t.Errorf("Day(%v) = %d, want %d", tc.now, got, tc.want)
+		}
+	}
+	if (League{}).Day(start) != 0 {
+		t.Error("unknown start should have no day")
+	}
+
+	for _, tc := range []struct {
+		l    League
+		want string
+	}{
+		{League{}, KindSoftcore},
+		{League{Hardcore: true}, KindHardcore},
+		{League{Hardcore: true, Event: true}, KindEvent},
+		{League{Hardcore: true, SSF: true, Event: true}, KindSSF},
+	} {
+		if got := tc.l.Kind(); got != tc.want {
+			t.Errorf("%+v.Kind() = %q, want %q", tc.l, got, tc.want)
+		}
+	}
+}
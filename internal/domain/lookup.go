@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+// LookupTargets are the chances of seeing a gem that attempt counts are
+// given for.
+var LookupTargets = []float64{0.5, 0.9, 0.99}
+
+// VariantLookup is where a transfigured gem comes from and how likely each
+// way of rolling for it is to show it.
+type VariantLookup struct {
+	Variant  GemVariantResult
+	BaseName string
+	Color    GemColor
+
+	// Variants is the base gem's rollable variant count, and PoolSize the
+	// color pool's.
+	Variants int
+	PoolSize int
+
+	// SpecificProb is the chance a transfigure of the base gem gives this
+	// variant, 1/Variants.
+	SpecificProb float64
+
+	// PoolProb is the chance a color-pool roll offers it among its
+	// FontDraws choices.
+	PoolProb float64
+}
+
+// LookupVariant finds the transfigured gem called name, case-insensitively.
+// Excluded variants are found with zero chances.
+func LookupVariant(r ProcessedResult, name string) (VariantLookup, bool) {
+	for _, e := range r.GemPicks {
+		for _, v := range e.Variants {
+			if !strings.EqualFold(v.Name, name) {
+				continue
+			}
+			l := VariantLookup{
+				Variant:      v,
+				BaseName:     e.BaseName,
+				Color:        e.Color,
+				Variants:     e.VariantCount,
+				PoolSize:     r.ColorStats[e.Color].PoolSize,
+				SpecificProb: v.Prob,
+			}
+			if n := float64(l.PoolSize); !v.Excluded && n > 0 {
+				l.PoolProb = 1 - math.Pow((n-1)/n, FontDraws)
+			}
+			return l, true
+		}
+	}
+	return VariantLookup{}, false
+}
+
+// AttemptsFor returns how many attempts, each with chance p of the outcome,
+// give at least chance target of seeing it once. It is 0 when p is 0.
+func AttemptsFor(p, target float64) int {
+	switch {
+	case p <= 0:
+		return 0
+	case p >= 1:
+		return 1
+	}
+	// Guard against 0.9 / 0.99 landing a hair above a whole number
+	n := math.Log(1-target) / math.Log(1-p)
+	return int(math.Ceil(n - 1e-9))
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookupVariant(t *testing.T) {
+	wiki := WikiData{
+		TransfigGems: map[GemColor][]string{
+			Red: {"Boneshatter of Carnage", "Boneshatter of Complex Trauma", "Cleave of Rage", "Cleave of Fury"},
+		},
+	}
+	r := ProcessGems(wiki, []GemPrice{{Name: "Boneshatter of Carnage", ChaosValue: 100}}, 5,
+		NewExclusions([]string{"Cleave of Fury"}))
+
+	l, ok := LookupVariant(r, "boneshatter of carnage")
+	if !ok {
+		t.Fatal("not found")
+	}
+	if l.BaseName != "Boneshatter" || l.Color != Red || l.Variants != 2 || l.PoolSize != 3 {
+		t.Errorf("unexpected lookup: %+v", l)
+	}
+	if l.SpecificProb != 0.5 {
+		t.Errorf("SpecificProb = %v, want 0.5", l.SpecificProb)
+	}
+	// 1 - (2/3)^3
+	if want := 19.0 / 27; math.Abs(l.PoolProb-want) > 1e-9 {
+		t.Errorf("PoolProb = %v, want %v", l.PoolProb, want)
+	}
+
+	if l, ok := LookupVariant(r, "Cleave of Fury"); !ok || l.SpecificProb != 0 || l.PoolProb != 0 {
+		t.Errorf("excluded variant: %+v, %v", l, ok)
+	}
+	if _, ok := LookupVariant(r, "Boneshatter"); ok {
+		t.Error("base gem found as a variant")
+	}
+}
+
+func TestAttemptsFor(t *testing.T) {
+	for _, tc := range []struct {
+		p, target float64
+		want      int
+	}{
+		{0.5, 0.5, 1},
+		{0.5, 0.9, 4},
+		{0.5, 0.99, 7},
+		{0.1, 0.9, 22},
+		{0.1, 0.99, 44},
+		{1, 0.99, 1},
+		{0, 0.5, 0},
+	} {
+		if got := AttemptsFor(tc.p, tc.target); got != tc.want {
+			t.Errorf("AttemptsFor(%v, %v) = %d, want %d", tc.p, tc.target, got, tc.want)
+		}
+	}
+}
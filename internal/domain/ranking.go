@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// LiquidityListings is the listing count at which a variant's price counts in
+// full towards LiquidityEV. Thinner markets are weighted down linearly.
+const LiquidityListings = 10
+
+// BestOfEV returns the expected best price of k draws from this gem's variants.
+func (e GemEntry) BestOfEV(k int) float64 {
+	vs := e.Rollable()
+	prices := make([]float64, len(vs))
+	for i, v := range vs {
+		prices[i] = v.SellPrice
+	}
+	return bestOfEV(prices, k)
+}
+
+// Median returns the median variant price, i.e. the typical single outcome.
+func (e GemEntry) Median() float64 {
+	vs := e.Rollable()
+	n := len(vs)
+	if n == 0 {
+		return 0
+	}
+	prices := make([]float64, n)
+	for i, v := range vs {
+		prices[i] = v.SellPrice
+	}
+	sort.Float64s(prices)
+	if n%2 == 1 {
+		return prices[n/2]
+	}
+	return (prices[n/2-1] + prices[n/2]) / 2
+}
+
+// MaxPrice returns the price of the most valuable variant.
+func (e GemEntry) MaxPrice() float64 {
+	var best float64
+	for _, v := range e.Rollable() {
+		best = math.Max(best, v.SellPrice)
+	}
+	return best
+}
+
+// MinPrice returns the price of the least valuable variant.
+func (e GemEntry) MinPrice() float64 {
+	vs := e.Rollable()
+	if len(vs) == 0 {
+		return 0
+	}
+	worst := vs[0].SellPrice
+	for _, v := range vs[1:] {
+		worst = math.Min(worst, v.SellPrice)
+	}
+	return worst
+}
+
+// ListedCount returns how many variants have a poe.ninja listing.
+func (e GemEntry) ListedCount() int {
+	n := 0
+	for _, v := range e.Rollable() {
+		if v.Listed {
+			n++
+		}
+	}
+	return n
+}
+
+// MinCount returns the fewest listings of any variant, 0 if one is unlisted.
+func (e GemEntry) MinCount() int {
+	vs := e.Rollable()
+	if len(vs) == 0 {
+		return 0
+	}
+	least := vs[0].Count
+	for _, v := range vs[1:] {
+		least = min(least, v.Count)
+	}
+	return least
+}
+
+// MaxCount returns the most listings of any variant.
+func (e GemEntry) MaxCount() int {
+	most := 0
+	for _, v := range e.Rollable() {
+		most = max(most, v.Count)
+	}
+	return most
+}
+
+// TotalCount returns the listings summed over all variants.
+func (e GemEntry) TotalCount() int {
+	n := 0
+	for _, v := range e.Rollable() {
+		n += v.Count
+	}
+	return n
+}
+
+// StdDev returns the standard deviation of a single roll's outcome.
+func (e GemEntry) StdDev() float64 {
+	var variance float64
+	for _, v := range e.Variants {
+		d := v.SellPrice - e.EV
+		variance += d * d * v.Prob
+	}
+	return math.Sqrt(variance)
+}
+
+// LiquidityEV returns the EV with each variant's price scaled by
+// min(1, listings/LiquidityListings), so thinly traded prices count less.
+func (e GemEntry) LiquidityEV() float64 {
+	var ev float64
+	for _, v := range e.Variants {
+		w := math.Min(1, float64(v.Count)/LiquidityListings)
+		ev += v.SellPrice * v.Prob * w
+	}
+	return ev
+}
+
+// RiskAdjustedEV returns EV per chaos of standard deviation. The deviation is
+// floored at 1c so gems whose variants all sell alike don't divide by zero.
+func (e GemEntry) RiskAdjustedEV() float64 {
+	return e.EV / math.Max(e.StdDev(), 1)
+}
+
+// RankStrategy scores gem entries for ordering, highest first.
+type RankStrategy struct {
+	Name  string
+	Score func(GemEntry) float64
+	Ratio bool // score is unitless rather than chaos
+}
+
+// FormatScore formats a score produced by this strategy.
+func (s RankStrategy) FormatScore(v float64) string {
+	if math.IsInf(v, -1) {
+		return FormatChaos(0)
+	}
+	if s.Ratio {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return FormatChaosSigned(v)
+}
+
+// netProfitScore ranks gems whose base gem isn't priced last, rather than as
+// if the base gem were free.
+func netProfitScore(e GemEntry) float64 {
+	if !e.BasePriced {
+		return math.Inf(-1)
+	}
+	return e.NetProfit()
+}
+
+// Names of the built-in ranking strategies, for picking them out of
+// RankStrategies without relying on its order.
+const (
+	RankEV           = "EV"
+	RankNetProfit    = "Net profit"
+	RankBestOf       = "Best-of-3 EV" // FontDraws written out
+	RankMedian       = "Median"
+	RankMaxPrice     = "Max price"
+	RankLiquidityEV  = "Liquidity EV"
+	RankRiskAdjusted = "Risk-adjusted"
+)
+
+// RankStrategies are the built-in orderings, EV first.
+var RankStrategies = []RankStrategy{
+	{Name: RankEV, Score: func(e GemEntry) float64 { return e.EV }},
+	{Name: RankNetProfit, Score: netProfitScore},
+	{Name: RankBestOf, Score: func(e GemEntry) float64 { return e.BestOfEV(FontDraws) }},
+	{Name: RankMedian, Score: GemEntry.Median},
+	{Name: RankMaxPrice, Score: GemEntry.MaxPrice},
+	{Name: RankLiquidityEV, Score: GemEntry.LiquidityEV},
+	{Name: RankRiskAdjusted, Score: GemEntry.RiskAdjustedEV, Ratio: true},
+}
+
+// RankGems returns a copy of entries sorted by strategy score, highest first.
+// Ties keep their input order.
+func RankGems(entries []GemEntry, s RankStrategy) []GemEntry {
+	type scored struct {
+		entry GemEntry
+		score float64
+	}
+	tmp := make([]scored, len(entries))
+	for i, e := range entries {
+		tmp[i] = scored{e, s.Score(e)}
+	}
+	sort.SliceStable(tmp, func(i, j int) bool {
+		return tmp[i].score > tmp[j].score
+	})
+
+	ranked := make([]GemEntry, len(tmp))
+	for i, t := range tmp {
+		ranked[i] = t.entry
+	}
+	return ranked
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestGemEntryStats(t *testing.T) {
+	v := GemEntry{
+		BaseName: "Volatile",
+		EV:       50,
+		Variants: []GemVariantResult{
+			{Name: "Volatile of A", SellPrice: 100, Prob: 0.5, Count: 1},
+			{Name: "Volatile of B", SellPrice: 0, Prob: 0.5},
+		},
+	}
+	if got := v.Median(); got != 50 {
+		t.Errorf("Median = %.2f, want 50", got)
+	}
+	if got := v.MaxPrice(); got != 100 {
+		t.Errorf("MaxPrice = %.2f, want 100", got)
+	}
+	if got := v.MinPrice(); got != 0 {
+		t.Errorf("MinPrice = %.2f, want 0", got)
+	}
+	if got := v.StdDev(); math.Abs(got-50) > 0.01 {
+		t.Errorf("StdDev = %.2f, want 50", got)
+	}
+	// Only 1 of 10 listings: 100 * 0.5 * 0.1
+	if got := v.LiquidityEV(); math.Abs(got-5) > 0.01 {
+		t.Errorf("LiquidityEV = %.2f, want 5", got)
+	}
+	// Best of 3 from [100, 0]: P(at least one 100) = 1 - 0.5^3
+	if got := v.BestOfEV(3); math.Abs(got-87.5) > 0.01 {
+		t.Errorf("BestOfEV = %.2f, want 87.5", got)
+	}
+}
+
+func rankStrategy(t *testing.T, name string) RankStrategy {
+	t.Helper()
+	for _, s := range RankStrategies {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no strategy named %q", name)
+	return RankStrategy{}
+}
+
+func TestRankGems(t *testing.T) {
+	entries := []GemEntry{
+		{BaseName: "Volatile", EV: 50, BaseCost: 45, BasePriced: true, Variants: []GemVariantResult{
+			{Name: "Volatile of A", SellPrice: 100, Prob: 0.5, Count: 1},
+			{Name: "Volatile of B", SellPrice: 0, Prob: 0.5},
+		}},
+		{BaseName: "Steady", EV: 30, BaseCost: 1, BasePriced: true, Variants: []GemVariantResult{
+			{Name: "Steady of A", SellPrice: 31, Prob: 0.5, Count: 20},
+			{Name: "Steady of B", SellPrice: 29, Prob: 0.5, Count: 20},
+		}},
+	}
+	tests := []struct {
+		strategy string
+		first    string
+	}{
+		{RankEV, "Volatile"},
+		{RankNetProfit, "Steady"},
+		{RankMaxPrice, "Volatile"},
+		{RankLiquidityEV, "Steady"},
+		{RankRiskAdjusted, "Steady"},
+	}
+	for _, tt := range tests {
+		ranked := RankGems(entries, rankStrategy(t, tt.strategy))
+		if ranked[0].BaseName != tt.first {
+			t.Errorf("%s: first = %q, want %q", tt.strategy, ranked[0].BaseName, tt.first)
+		}
+	}
+	if entries[0].BaseName != "Volatile" {
+		t.Error("RankGems modified its input")
+	}
+}
+
+func TestRankBestOfName(t *testing.T) {
+	if want := fmt.Sprintf("Best-of-%d EV", FontDraws); RankBestOf != want {
+		t.Errorf("RankBestOf = %q, want %q to match FontDraws", RankBestOf, want)
+	}
+}
+
+func TestRankNetProfitUnpricedBase(t *testing.T) {
+	entries := []GemEntry{
+		// The highest EV, but its base gem's cost is unknown
+		{BaseName: "Unpriced", EV: 500},
+		{BaseName: "Costly", EV: 30, BaseCost: 40, BasePriced: true},
+	}
+	net := rankStrategy(t, RankNetProfit)
+	ranked := RankGems(entries, net)
+	if got := ranked[len(ranked)-1].BaseName; got != "Unpriced" {
+		t.Errorf("last = %q, want the gem with an unpriced base", got)
+	}
+	if got := net.FormatScore(net.Score(entries[0])); got != FormatChaos(0) {
+		t.Errorf("unpriced base scored %s", got)
+	}
+}
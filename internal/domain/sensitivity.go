@@ -0,0 +1,86 @@
+package domain
+
+import "sort"
+
+// SensitivityDrop is the relative price drop used to stress each gem.
+const SensitivityDrop = 0.5
+
+// GemContribution is a single gem's share of its color's pool EV.
+type GemContribution struct {
+	Name         string
+	BaseName     string
+	SellPrice    float64
+	PBest        float64 // probability this gem is the best of FontDraws draws
+	Contribution float64 // PBest × SellPrice
+	Share        float64 // Contribution / PoolEV
+	DropDelta    float64 // change in pool EV if SellPrice fell by SensitivityDrop
+}
+
+// PoolSensitivity ranks every gem in a color pool by its marginal contribution
+// to the pool EV, highest first. Gems with equal prices split their combined
+// best-of probability evenly so ties don't depend on sort order.
+func PoolSensitivity(result ProcessedResult, color GemColor) []GemContribution {
+	pool := colorPool(result, color)
+	if len(pool) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].SellPrice > pool[j].SellPrice
+	})
+
+	n := len(pool)
+	prices := make([]float64, n)
+	for i := range pool {
+		prices[i] = pool[i].SellPrice
+	}
+
+	for i := 0; i < n; {
+		j := i
+		var pTie float64
+		for j < n && pool[j].SellPrice == pool[i].SellPrice {
+			pTie += bestOfProb(j, n, FontDraws)
+			j++
+		}
+		for k := i; k < j; k++ {
+			pool[k].PBest = pTie / float64(j-i)
+			pool[k].Contribution = pool[k].PBest * pool[k].SellPrice
+		}
+		i = j
+	}
+
+	poolEV := bestOfEV(prices, FontDraws)
+	for i := range pool {
+		if poolEV > 0 {
+			pool[i].Share = pool[i].Contribution / poolEV
+		}
+		orig := prices[i]
+		prices[i] = orig * (1 - SensitivityDrop)
+		pool[i].DropDelta = bestOfEV(prices, FontDraws) - poolEV
+		prices[i] = orig
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].Contribution > pool[j].Contribution
+	})
+	return pool
+}
+
+// colorPool lists every rollable transfigured gem of a color with its price,
+// in GemPicks order. PBest and the derived fields are left zero.
+func colorPool(result ProcessedResult, color GemColor) []GemContribution {
+	var pool []GemContribution
+	for _, e := range result.GemPicks {
+		if e.Color != color {
+			continue
+		}
+		for _, v := range e.Rollable() {
+			pool = append(pool, GemContribution{
+				Name:      v.Name,
+				BaseName:  e.BaseName,
+				SellPrice: v.SellPrice,
+			})
+		}
+	}
+	return pool
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPoolSensitivity(t *testing.T) {
+	wiki := WikiData{
+		BaseGems: map[GemColor][]string{
+			Red:   {"Boneshatter"},
+			Green: {},
+			Blue:  {},
+		},
+		TransfigGems: map[GemColor][]string{
+			Red:   {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+			Green: {},
+			Blue:  {},
+		},
+	}
+	prices := []GemPrice{
+		{Name: "Boneshatter of Carnage", ChaosValue: 100},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50},
+	}
+	result := ProcessGems(wiki, prices, 5, nil)
+
+	contribs := PoolSensitivity(result, Red)
+	if len(contribs) != 2 {
+		t.Fatalf("expected 2 contributions, got %d", len(contribs))
+	}
+
+	// Pool EV = 93.75 (see TestProcessGems_EVCalculation)
+	// Carnage: 0.875 * 100 = 87.5; halved -> [50, 50] -> EV 50, delta -43.75
+	// Complex Trauma: 0.125 * 50 = 6.25; halved -> 100*0.875 + 25*0.125 = 90.625, delta -3.125
+	top := contribs[0]
+	if top.Name != "Boneshatter of Carnage" {
+		t.Fatalf("expected Carnage first, got %q", top.Name)
+	}
+	if math.Abs(top.Contribution-87.5) > 0.01 {
+		t.Errorf("expected contribution=87.5, got %.2f", top.Contribution)
+	}
+	if math.Abs(top.DropDelta+43.75) > 0.01 {
+		t.Errorf("expected drop delta=-43.75, got %.2f", top.DropDelta)
+	}
+	if math.Abs(contribs[1].DropDelta+3.125) > 0.01 {
+		t.Errorf("expected drop delta=-3.125, got %.3f", contribs[1].DropDelta)
+	}
+
+	var sum float64
+	for _, c := range contribs {
+		sum += c.Contribution
+	}
+	if math.Abs(sum-result.ColorStats[Red].PoolEV) > 0.01 {
+		t.Errorf("contributions sum to %.2f, want pool EV %.2f", sum, result.ColorStats[Red].PoolEV)
+	}
+}
+
+func TestPoolSensitivity_TiesSplitEvenly(t *testing.T) {
+	result := ProcessedResult{
+		GemPicks: []GemEntry{{
+			BaseName: "Arc",
+			Color:    Blue,
+			Variants: []GemVariantResult{
+				{Name: "Arc of Surging", SellPrice: 20},
+				{Name: "Arc of Oscillating", SellPrice: 20},
+			},
+		}},
+	}
+
+	contribs := PoolSensitivity(result, Blue)
+	if len(contribs) != 2 {
+		t.Fatalf("expected 2 contributions, got %d", len(contribs))
+	}
+	for _, c := range contribs {
+		if math.Abs(c.PBest-0.5) > 1e-9 {
+			t.Errorf("%s: expected PBest=0.5, got %.4f", c.Name, c.PBest)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package duration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	var d Duration
+	if err := json.Unmarshal([]byte(`"1h30m"`), &d); err != nil || time.Duration(d) != 90*time.Minute {
+		t.Errorf("unmarshal = %v, %v", time.Duration(d), err)
+	}
+	if b, err := json.Marshal(Duration(5 * time.Minute)); err != nil || string(b) != `"5m0s"` {
+		t.Errorf("marshal = %s, %v", b, err)
+	}
+	for _, bad := range []string{`300`, `"soon"`} {
+		if err := json.Unmarshal([]byte(bad), &d); err == nil {
+			t.Errorf("%s accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// maxSameSecond is how many exports with the same name WriteFile numbers
+// before giving up.
+const maxSameSecond = 100
+
+// FileName returns a timestamped export file name such as
+// gemcheck-Settlers-red-20260101-120000.csv.
+func FileName(league, label string, f Format, at time.Time) string {
+	parts := []string{"gemcheck"}
+	for _, p := range []string{league, label} {
+		if p = strings.Trim(unsafeChars.ReplaceAllString(p, "_"), "_"); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	parts = append(parts, at.Local().Format("20060102-150405"))
+	return strings.Join(parts, "-") + "." + f.Ext()
+}
+
+// WriteFile writes t to a new timestamped file in dir and returns its path.
+// Exports within the same second are numbered: -2, -3 and so on.
+func WriteFile(dir, league, label string, f Format, t Table, at time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := FileName(league, label, f, at)
+	ext := "." + f.Ext()
+	base := strings.TrimSuffix(name, ext)
+	var (
+		path string
+		file *os.File
+		err  error
+	)
+	for n := 1; n <= maxSameSecond; n++ {
+		path = filepath.Join(dir, name)
+		if n > 1 {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, n, ext))
+		}
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if !errors.Is(err, fs.ErrExist) {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	werr := t.Write(file, f)
+	cerr := file.Close()
+	if werr != nil {
+		return "", fmt.Errorf("%s: %w", path, werr)
+	}
+	if cerr != nil {
+		return "", cerr
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, nil
+}
@@ -0,0 +1,28 @@
+package export
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFileSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	table := Table{Header: []string{"Name"}, Rows: [][]any{{"Arc"}}}
+
+	want := []string{
+		"gemcheck-Settlers-red-20260101-120000.csv",
+		"gemcheck-Settlers-red-20260101-120000-2.csv",
+		"gemcheck-Settlers-red-20260101-120000-3.csv",
+	}
+	for _, name := range want {
+		path, err := WriteFile(dir, "Settlers", "red", CSV, table, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(path) != name {
+			t.Errorf("got %s, want %s", filepath.Base(path), name)
+		}
+	}
+}
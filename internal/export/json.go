@@ -0,0 +1,166 @@
+// Package export converts a domain.ProcessedResult into stable formats for
+// scripts and spreadsheets. The JSON schema is versioned separately from the
+// domain types so internal refactors don't break consumers.
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// SchemaVersion is bumped whenever a field is renamed, removed or changes
+// meaning. Adding fields does not bump it.
+const SchemaVersion = 1
+
+// Result is the top-level JSON document.
+type Result struct {
+	Schema      int       `json:"schema"`
+	League      string    `json:"league"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Totals      Totals    `json:"totals"`
+	Pools       []Pool    `json:"pools"`
+	Gems        []Gem     `json:"gems"`
+}
+
+// Totals counts the raw input.
+type Totals struct {
+	PriceLines   int `json:"price_lines"`
+	Transfigured int `json:"transfigured"`
+}
+
+// Pool is one color's font pool.
+type Pool struct {
+	Color    string  `json:"color"` // "r", "g" or "b"
+	Label    string  `json:"label"`
+	Size     int     `json:"size"`
+	EV       float64 `json:"ev"`
+	Excluded int     `json:"excluded"`
+	Bingo    []Bingo `json:"bingo"`
+}
+
+// Bingo is a top gem in a pool with its chance to show up in a font roll.
+type Bingo struct {
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Prob     float64 `json:"prob"`
+	Listings int     `json:"listings"`
+}
+
+// Gem is a base gem and its transfigured variants.
+type Gem struct {
+	Base         string    `json:"base"`
+	Color        string    `json:"color"`
+	EV           float64   `json:"ev"`
+	BaseCost     float64   `json:"base_cost"`
+	NetProfit    *float64  `json:"net_profit"` // nil when the base gem is unpriced
+	VariantCount int       `json:"variant_count"`
+	Variants     []Variant `json:"variants"`
+}
+
+// Variant is one transfigured gem.
+type Variant struct {
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Prob     float64 `json:"prob"`
+	Listings int     `json:"listings"`
+	Listed   bool    `json:"listed"`
+	Excluded bool    `json:"excluded"`
+}
+
+// NewResult converts a processed result into the export schema. Pools are in
+// red, green, blue order and gems keep the result's EV order.
+func NewResult(league string, at time.Time, r domain.ProcessedResult) Result {
+	out := Result{
+		Schema:      SchemaVersion,
+		League:      league,
+		GeneratedAt: at.UTC(),
+		Totals:      Totals{PriceLines: r.TotalLines, Transfigured: r.TotalTransfig},
+		Pools:       make([]Pool, 0, len(domain.AllColors)),
+		Gems:        make([]Gem, 0, len(r.GemPicks)),
+	}
+	for _, c := range domain.AllColors {
+		stats := r.ColorStats[c]
+		pool := Pool{
+			Color:    string(c),
+			Label:    c.Label(),
+			Size:     stats.PoolSize,
+			EV:       stats.PoolEV,
+			Excluded: stats.Excluded,
+			Bingo:    make([]Bingo, 0, len(stats.Bingo)),
+		}
+		for _, b := range stats.Bingo {
+			pool.Bingo = append(pool.Bingo, Bingo{Name: b.Name, Price: b.SellPrice, Prob: b.Prob, Listings: b.Count})
+		}
+		out.Pools = append(out.Pools, pool)
+	}
+	for _, e := range r.GemPicks {
+		out.Gems = append(out.Gems, newGem(e))
+	}
+	return out
+}
+
+func newGem(e domain.GemEntry) Gem {
+	g := Gem{
+		Base:         e.BaseName,
+		Color:        string(e.Color),
+		EV:           e.EV,
+		BaseCost:     e.BaseCost,
+		VariantCount: e.VariantCount,
+		Variants:     make([]Variant, 0, len(e.Variants)),
+	}
+	if e.BasePriced {
+		net := e.NetProfit()
+		g.NetProfit = &net
+	}
+	for _, v := range e.Variants {
+		g.Variants = append(g.Variants, Variant{
+			Name:     v.Name,
+			Price:    v.SellPrice,
+			Prob:     v.Prob,
+			Listings: v.Count,
+			Listed:   v.Listed,
+			Excluded: v.Excluded,
+		})
+	}
+	return g
+}
+
+// WriteJSON encodes v as JSON, indented unless compact is set.
+func WriteJSON(w io.Writer, v any, compact bool) error {
+	enc := json.NewEncoder(w)
+	if !compact {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
+
+// ForColor returns a copy of r with only the given color's pool and gems.
+func (r Result) ForColor(c domain.GemColor) Result {
+	pools, gems := r.Pools, r.Gems
+	r.Pools, r.Gems = []Pool{}, []Gem{}
+	for _, p := range pools {
+		if p.Color == string(c) {
+			r.Pools = append(r.Pools, p)
+		}
+	}
+	for _, g := range gems {
+		if g.Color == string(c) {
+			r.Gems = append(r.Gems, g)
+		}
+	}
+	return r
+}
+
+// Gem looks up a gem by base name, case-insensitively.
+func (r Result) Gem(base string) (Gem, bool) {
+	for _, g := range r.Gems {
+		if strings.EqualFold(g.Base, base) {
+			return g, true
+		}
+	}
+	return Gem{}, false
+}
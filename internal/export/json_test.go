@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+var testResult = domain.ProcessGems(
+	domain.WikiData{TransfigGems: map[domain.GemColor][]string{
+		domain.Red: {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+	}},
+	[]domain.GemPrice{
+		{Name: "Boneshatter", ChaosValue: 5},
+		{Name: "Boneshatter of Carnage", ChaosValue: 100, Count: 12},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50, Count: 30},
+	},
+	domain.DefaultTopN,
+	nil,
+)
+
+func keys(m map[string]any) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// TestJSONSchema pins the field names documented in the README. Changing
+// any of them needs a SchemaVersion bump.
+func TestJSONSchema(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, NewResult("Settlers", at, testResult), true); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	check := func(what string, m any, want ...string) {
+		t.Helper()
+		if got := keys(m.(map[string]any)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s keys = %v, want %v", what, got, want)
+		}
+	}
+	check("result", doc, "gems", "generated_at", "league", "pools", "schema", "totals")
+	check("totals", doc["totals"], "price_lines", "transfigured")
+
+	pools := doc["pools"].([]any)
+	if len(pools) != 3 {
+		t.Fatalf("expected 3 pools, got %d", len(pools))
+	}
+	check("pool", pools[0], "bingo", "color", "ev", "excluded", "label", "size")
+	check("bingo", pools[0].(map[string]any)["bingo"].([]any)[0], "listings", "name", "price", "prob")
+
+	gems := doc["gems"].([]any)
+	if len(gems) != 1 {
+		t.Fatalf("expected 1 gem, got %d", len(gems))
+	}
+	check("gem", gems[0], "base", "base_cost", "color", "ev", "net_profit", "variant_count", "variants")
+	check("variant", gems[0].(map[string]any)["variants"].([]any)[0],
+		"excluded", "listed", "listings", "name", "price", "prob")
+
+	if doc["schema"].(float64) != SchemaVersion || doc["generated_at"] != "2026-01-01T12:00:00Z" {
+		t.Errorf("unexpected header: schema=%v generated_at=%v", doc["schema"], doc["generated_at"])
+	}
+	if g := gems[0].(map[string]any); g["ev"].(float64) != 75 || g["net_profit"].(float64) != 70 {
+		t.Errorf("unexpected gem values: ev=%v net_profit=%v", g["ev"], g["net_profit"])
+	}
+}
+
+func TestJSONUnpricedBase(t *testing.T) {
+	e := testResult.GemPicks[0]
+	e.BasePriced, e.BaseCost = false, 0
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, newGem(e), true); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := doc["net_profit"]; !ok || v != nil {
+		t.Errorf("net_profit = %v, want null", v)
+	}
+}
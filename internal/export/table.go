@@ -0,0 +1,267 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// Format is a tabular output format.
+type Format string
+
+const (
+	CSV      Format = "csv"
+	TSV      Format = "tsv"
+	Markdown Format = "md"
+)
+
+// ParseFormat accepts a format name or file extension.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "csv":
+		return CSV, nil
+	case "tsv", "tab":
+		return TSV, nil
+	case "md", "markdown":
+		return Markdown, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want csv, tsv or md)", s)
+}
+
+// Ext returns the file extension for the format, without the dot.
+func (f Format) Ext() string { return string(f) }
+
+// Cell kinds that format differently per output. CSV and TSV keep full
+// precision for spreadsheets; Markdown rounds for reading.
+type (
+	chaos   float64 // a price in chaos
+	percent float64 // a probability in [0, 1]
+)
+
+// Table is a header and rows of cells: strings, ints, bools, chaos or percent.
+type Table struct {
+	Header []string
+	Rows   [][]any
+}
+
+// Tables lists the table names accepted by NewTable.
+var Tables = []string{"gems", "variants", "pools", "bingo"}
+
+// NewTable builds the named table from a result. Gems appear in the order
+// given; pass a ranked copy of GemPicks to export a ranking.
+func NewTable(name string, r domain.ProcessedResult, gems []domain.GemEntry) (Table, error) {
+	switch name {
+	case "gems":
+		return GemsTable(gems, nil), nil
+	case "variants":
+		return VariantsTable(gems), nil
+	case "pools":
+		return PoolsTable(r), nil
+	case "bingo":
+		return BingoTable(r), nil
+	}
+	return Table{}, fmt.Errorf("unknown table %q (want %s)", name, strings.Join(Tables, ", "))
+}
+
+// GemsTable has one row per base gem. When rank is non-nil its score is
+// added as the last column.
+func GemsTable(gems []domain.GemEntry, rank *domain.RankStrategy) Table {
+	t := Table{Header: []string{"Rank", "Gem", "Color", "EV", "Base cost", "Net profit", "Variants", "Best variant", "Best price"}}
+	if rank != nil {
+		t.Header = append(t.Header, "Score: "+rank.Name)
+	}
+	for i, e := range gems {
+		var best domain.GemVariantResult
+		for _, v := range e.Rollable() {
+			if v.SellPrice > best.SellPrice {
+				best = v
+			}
+		}
+		// Without a base price there is no net profit to give
+		var net any = ""
+		if e.BasePriced {
+			net = chaos(e.NetProfit())
+		}
+		row := []any{i + 1, e.BaseName, e.Color.Label(), chaos(e.EV), chaos(e.BaseCost),
+			net, e.VariantCount, best.Name, chaos(best.SellPrice)}
+		if rank != nil {
+			row = append(row, scoreCell(*rank, e))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// VariantsTable has one row per transfigured gem, with its base gem's EV.
+func VariantsTable(gems []domain.GemEntry) Table {
+	t := Table{Header: []string{"Gem", "Color", "EV", "Variant", "Price", "Chance", "Listings", "Listed", "Excluded"}}
+	for _, e := range gems {
+		for _, v := range e.Variants {
+			t.Rows = append(t.Rows, []any{e.BaseName, e.Color.Label(), chaos(e.EV), v.Name,
+				chaos(v.SellPrice), percent(v.Prob), v.Count, v.Listed, v.Excluded})
+		}
+	}
+	return t
+}
+
+// PoolsTable has one row per color pool.
+func PoolsTable(r domain.ProcessedResult) Table {
+	t := Table{Header: []string{"Color", "Pool size", "Pool EV", "Excluded"}}
+	for _, c := range domain.AllColors {
+		s := r.ColorStats[c]
+		t.Rows = append(t.Rows, []any{c.Label(), s.PoolSize, chaos(s.PoolEV), s.Excluded})
+	}
+	return t
+}
+
+// BingoTable lists each pool's top gems and their chance per font roll.
+func BingoTable(r domain.ProcessedResult) Table {
+	t := Table{Header: []string{"Color", "Gem", "Price", "Chance", "Listings"}}
+	for _, c := range domain.AllColors {
+		for _, b := range r.ColorStats[c].Bingo {
+			t.Rows = append(t.Rows, []any{c.Label(), b.Name, chaos(b.SellPrice), percent(b.Prob), b.Count})
+		}
+	}
+	return t
+}
+
+// Write renders t in format f.
+func (t Table) Write(w io.Writer, f Format) error {
+	switch f {
+	case CSV:
+		return t.writeCSV(w)
+	case TSV:
+		return t.writeTSV(w)
+	case Markdown:
+		return t.writeMarkdown(w)
+	}
+	return fmt.Errorf("unknown export format %q", f)
+}
+
+func (t Table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Header); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		rec := make([]string, len(row))
+		for i, c := range row {
+			rec[i] = rawCell(c)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// tsvEscaper replaces the separators TSV can't quote.
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+func (t Table) writeTSV(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(strings.Join(t.Header, "\t") + "\n")
+	for _, row := range t.Rows {
+		for i, c := range row {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(tsvEscaper.Replace(rawCell(c)))
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (t Table) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, c := range cells {
+			b.WriteString(" " + strings.ReplaceAll(c, "|", `\|`) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(t.Header)
+	b.WriteString("|")
+	for i := range t.Header {
+		if len(t.Rows) > 0 && isNumeric(t.Rows[0][i]) {
+			b.WriteString("---:|")
+		} else {
+			b.WriteString("---|")
+		}
+	}
+	b.WriteString("\n")
+
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = prettyCell(c)
+		}
+		writeRow(cells)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// scoreCell is a ranking score as a cell; gems the strategy can't score are
+// left blank.
+func scoreCell(s domain.RankStrategy, e domain.GemEntry) any {
+	v := s.Score(e)
+	switch {
+	case math.IsInf(v, 0) || math.IsNaN(v):
+		return ""
+	case s.Ratio:
+		return v
+	}
+	return chaos(v)
+}
+
+func isNumeric(c any) bool {
+	switch c.(type) {
+	case int, float64, chaos, percent:
+		return true
+	}
+	return false
+}
+
+// rawCell formats a cell for spreadsheets.
+func rawCell(c any) string {
+	switch v := c.(type) {
+	case chaos:
+		return strconv.FormatFloat(float64(v), 'f', 2, 64)
+	case percent:
+		return strconv.FormatFloat(float64(v), 'f', 6, 64)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(c)
+}
+
+// prettyCell formats a cell for reading.
+func prettyCell(c any) string {
+	switch v := c.(type) {
+	case chaos:
+		return domain.FormatChaos(float64(v))
+	case percent:
+		return domain.FormatPct(float64(v))
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case bool:
+		if v {
+			return "yes"
+		}
+		return ""
+	}
+	return fmt.Sprint(c)
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func TestTableWrite(t *testing.T) {
+	table := VariantsTable(testResult.GemPicks)
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{CSV, "Gem,Color,EV,Variant,Price,Chance,Listings,Listed,Excluded\n" +
+			"Boneshatter,Red,75.00,Boneshatter of Carnage,100.00,0.500000,12,true,false\n" +
+			"Boneshatter,Red,75.00,Boneshatter of Complex Trauma,50.00,0.500000,30,true,false\n"},
+		{TSV, "Gem\tColor\tEV\tVariant\tPrice\tChance\tListings\tListed\tExcluded\n" +
+			"Boneshatter\tRed\t75.00\tBoneshatter of Carnage\t100.00\t0.500000\t12\ttrue\tfalse\n" +
+			"Boneshatter\tRed\t75.00\tBoneshatter of Complex Trauma\t50.00\t0.500000\t30\ttrue\tfalse\n"},
+		{Markdown, "| Gem | Color | EV | Variant | Price | Chance | Listings | Listed | Excluded |\n" +
+			"|---|---|---:|---|---:|---:|---:|---|---|\n" +
+			"| Boneshatter | Red | 75.0c | Boneshatter of Carnage | 100c | 50.0% | 12 | yes |  |\n" +
+			"| Boneshatter | Red | 75.0c | Boneshatter of Complex Trauma | 50.0c | 50.0% | 30 | yes |  |\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := table.Write(&b, tt.format); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s output:\n%s\nwant:\n%s", tt.format, b.String(), tt.want)
+		}
+	}
+}
+
+func TestTableEscaping(t *testing.T) {
+	table := Table{Header: []string{"Name"}, Rows: [][]any{{"a|b,\"c\"\td"}}}
+	for format, want := range map[Format]string{
+		CSV:      "Name\n\"a|b,\"\"c\"\"\td\"\n",
+		TSV:      "Name\na|b,\"c\" d\n",
+		Markdown: "| Name |\n|---|\n| a\\|b,\"c\"\td |\n",
+	} {
+		var b strings.Builder
+		if err := table.Write(&b, format); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != want {
+			t.Errorf("%s: got %q, want %q", format, b.String(), want)
+		}
+	}
+}
+
+func TestGemsTableRank(t *testing.T) {
+	net := domain.RankStrategies[1]
+	table := GemsTable(testResult.GemPicks, &net)
+	if got := table.Header[len(table.Header)-1]; got != "Score: "+net.Name {
+		t.Errorf("last column = %q, want score column", got)
+	}
+	row := table.Rows[0]
+	if row[7] != "Boneshatter of Carnage" || row[len(row)-1] != chaos(70) {
+		t.Errorf("unexpected row: %v", row)
+	}
+}
+
+func TestGemsTableUnpricedBase(t *testing.T) {
+	e := testResult.GemPicks[0]
+	e.BasePriced, e.BaseCost = false, 0
+	if row := GemsTable([]domain.GemEntry{e}, nil).Rows[0]; row[5] != "" {
+		t.Errorf("net profit = %v, want blank", row[5])
+	}
+}
@@ -0,0 +1,297 @@
+// Package history records poe.ninja price fetches to append-only, per-league
+// files so prices and pool EV can be charted over a league's lifetime.
+//
+// Each league has one JSON-lines file under the store directory, one
+// snapshot per line. Files are compacted in place once they grow past
+// compactSize: recent snapshots are kept as-is and older ones are thinned to
+// one per hour, then one per day. A file that stays over compactSize after
+// compacting is compacted again only once it has grown by compactMargin.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// compactSize is the file size that triggers compaction on append.
+const compactSize = 8 << 20
+
+// compactMargin is how much a file must grow after compacting before it is
+// compacted again, so recent snapshots alone over compactSize don't get the
+// whole file rewritten on every append.
+const compactMargin = compactSize / 4
+
+// Retention tiers used by compaction.
+const (
+	keepAllFor    = 24 * time.Hour
+	keepHourlyFor = 30 * 24 * time.Hour
+)
+
+// Price is one gem's cheapest non-corrupted listing in a snapshot.
+type Price struct {
+	Name  string  `json:"n"`
+	Chaos float64 `json:"c"`
+	Count int     `json:"k,omitempty"`
+}
+
+// Snapshot is a single recorded fetch.
+type Snapshot struct {
+	Time   time.Time `json:"t"`
+	Prices []Price   `json:"p"`
+}
+
+// GemPrices converts the snapshot back into the form domain.ProcessGems takes.
+func (s Snapshot) GemPrices() []domain.GemPrice {
+	prices := make([]domain.GemPrice, len(s.Prices))
+	for i, p := range s.Prices {
+		prices[i] = domain.GemPrice{Name: p.Name, ChaosValue: p.Chaos, Count: p.Count}
+	}
+	return prices
+}
+
+// Point is a value at a point in time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Store is a directory of per-league history files.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+
+	// compacted is each league file's size after its last compaction.
+	compacted map[string]int64
+}
+
+// New creates a store rooted at dir, which is created on first write.
+func New(dir string) *Store {
+	return &Store{dir: dir, compacted: make(map[string]int64)}
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (s *Store) path(league string) string {
+	return filepath.Join(s.dir, unsafeChars.ReplaceAllString(league, "_")+".jsonl")
+}
+
+// Append records a fetch for a league. Corrupted listings are dropped and
+// each gem keeps only its cheapest price, matching domain.ProcessGems.
+func (s *Store) Append(league string, at time.Time, prices []domain.GemPrice) error {
+	snap := newSnapshot(at, prices)
+	line, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	path := s.path(league)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, werr := f.Write(append(line, '\n'))
+	cerr := f.Close()
+	if werr != nil {
+		return werr
+	}
+	if cerr != nil {
+		return cerr
+	}
+
+	limit := max(compactSize, s.compacted[league]+compactMargin)
+	if info, err := os.Stat(path); err == nil && info.Size() > limit {
+		return s.compactLocked(league, at)
+	}
+	return nil
+}
+
+func newSnapshot(at time.Time, prices []domain.GemPrice) Snapshot {
+	cheapest := make(map[string]Price)
+	for _, p := range prices {
+		if p.Corrupted {
+			continue
+		}
+		if existing, ok := cheapest[p.Name]; !ok || p.ChaosValue < existing.Chaos {
+			cheapest[p.Name] = Price{Name: p.Name, Chaos: p.ChaosValue, Count: p.Count}
+		}
+	}
+	snap := Snapshot{Time: at.UTC(), Prices: make([]Price, 0, len(cheapest))}
+	for _, p := range cheapest {
+		snap.Prices = append(snap.Prices, p)
+	}
+	sort.Slice(snap.Prices, func(i, j int) bool {
+		return snap.Prices[i].Name < snap.Prices[j].Name
+	})
+	return snap
+}
+
+// Snapshots returns every recorded snapshot for a league, oldest first.
+// Lines that fail to parse, such as a write cut short, are skipped.
+func (s *Store) Snapshots(league string) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked(league)
+}
+
+func (s *Store) readLocked(league string) ([]Snapshot, error) {
+	f, err := os.Open(s.path(league))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		var snap Snapshot
+		if json.Unmarshal(sc.Bytes(), &snap) == nil {
+			snaps = append(snaps, snap)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(snaps, func(i, j int) bool {
+		return snaps[i].Time.Before(snaps[j].Time)
+	})
+	return snaps, nil
+}
+
+// Compact rewrites a league's file keeping only the snapshots the retention
+// tiers allow, as of now.
+func (s *Store) Compact(league string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compactLocked(league, now)
+}
+
+func (s *Store) compactLocked(league string, now time.Time) error {
+	snaps, err := s.readLocked(league)
+	if err != nil {
+		return err
+	}
+	kept := thin(snaps, now)
+
+	path := s.path(league)
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, snap := range kept {
+		if err := enc.Encode(snap); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	s.compacted[league] = info.Size()
+	return nil
+}
+
+// thin applies the retention tiers to time-sorted snapshots: keep everything
+// from the last keepAllFor, the first snapshot of each hour up to
+// keepHourlyFor, and the first of each day before that.
+func thin(snaps []Snapshot, now time.Time) []Snapshot {
+	var kept []Snapshot
+	seen := make(map[time.Time]bool)
+	for _, snap := range snaps {
+		age := now.Sub(snap.Time)
+		var bucket time.Time
+		switch {
+		case age < keepAllFor:
+			kept = append(kept, snap)
+			continue
+		case age < keepHourlyFor:
+			bucket = snap.Time.Truncate(time.Hour)
+		default:
+			bucket = snap.Time.Truncate(24 * time.Hour)
+		}
+		if !seen[bucket] {
+			seen[bucket] = true
+			kept = append(kept, snap)
+		}
+	}
+	return kept
+}
+
+// PriceSeries returns a gem's recorded price over time. Snapshots where the
+// gem had no listing are skipped.
+func (s *Store) PriceSeries(league, gem string) ([]Point, error) {
+	snaps, err := s.Snapshots(league)
+	if err != nil {
+		return nil, err
+	}
+	var points []Point
+	for _, snap := range snaps {
+		for _, p := range snap.Prices {
+			if p.Name == gem {
+				points = append(points, Point{Time: snap.Time, Value: p.Chaos})
+				break
+			}
+		}
+	}
+	return points, nil
+}
+
+// PoolEVSeries replays each snapshot through domain.ProcessGems and returns
+// every color's pool EV over time. The current wiki data is used throughout.
+func (s *Store) PoolEVSeries(league string, wiki domain.WikiData, excluded domain.Exclusions) (map[domain.GemColor][]Point, error) {
+	series := make(map[domain.GemColor][]Point)
+	err := s.Replay(league, wiki, excluded, func(at time.Time, result domain.ProcessedResult) {
+		for _, c := range domain.AllColors {
+			series[c] = append(series[c], Point{Time: at, Value: result.ColorStats[c].PoolEV})
+		}
+	})
+	return series, err
+}
+
+// Replay runs domain.ProcessGems over each snapshot, oldest first, and hands
+// the result to fn.
+func (s *Store) Replay(league string, wiki domain.WikiData, excluded domain.Exclusions, fn func(time.Time, domain.ProcessedResult)) error {
+	snaps, err := s.Snapshots(league)
+	if err != nil {
+		return err
+	}
+	for _, snap := range snaps {
+		fn(snap.Time, domain.ProcessGems(wiki, snap.GemPrices(), 0, excluded))
+	}
+	return nil
+}
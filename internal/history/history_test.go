@@ -0,0 +1,148 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func TestAppendAndQuery(t *testing.T) {
+	s := New(t.TempDir())
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	fetches := [][]domain.GemPrice{
+		{
+			{Name: "Boneshatter of Carnage", ChaosValue: 100, Count: 5},
+			{Name: "Boneshatter of Carnage", ChaosValue: 300, Corrupted: true},
+			{Name: "Boneshatter of Complex Trauma", ChaosValue: 50},
+		},
+		{
+			{Name: "Boneshatter of Complex Trauma", ChaosValue: 60},
+		},
+	}
+	for i, prices := range fetches {
+		if err := s.Append("Hardcore Settlers", t0.Add(time.Duration(i)*time.Hour), prices); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	carnage, err := s.PriceSeries("Hardcore Settlers", "Boneshatter of Carnage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(carnage) != 1 || carnage[0].Value != 100 {
+		t.Errorf("expected one non-corrupted Carnage point at 100c, got %+v", carnage)
+	}
+
+	wiki := domain.WikiData{TransfigGems: map[domain.GemColor][]string{
+		domain.Red: {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+	}}
+	pools, err := s.PoolEVSeries("Hardcore Settlers", wiki, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	red := pools[domain.Red]
+	if len(red) != 2 {
+		t.Fatalf("expected 2 red points, got %d", len(red))
+	}
+	// [100, 50] -> 93.75, then [60, 0] -> 52.5
+	if math.Abs(red[0].Value-93.75) > 0.01 || math.Abs(red[1].Value-52.5) > 0.01 {
+		t.Errorf("unexpected red pool EV series: %+v", red)
+	}
+
+	if other, _ := s.Snapshots("Settlers"); len(other) != 0 {
+		t.Errorf("leagues not isolated: %d snapshots in Settlers", len(other))
+	}
+}
+
+func TestCompact(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := []domain.GemPrice{{Name: "Arc of Surging", ChaosValue: 1}}
+
+	// Every 15 minutes: within a day (all kept), within 30 days (hourly),
+	// and older (daily).
+	var times []time.Time
+	for _, start := range []time.Time{
+		now.Add(-2 * time.Hour),
+		now.Add(-48 * time.Hour),
+		now.Add(-60 * 24 * time.Hour),
+	} {
+		for i := range 4 {
+			times = append(times, start.Add(time.Duration(i)*15*time.Minute))
+		}
+	}
+	for _, at := range times {
+		if err := s.Append("Standard", at, prices); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Compact("Standard", now); err != nil {
+		t.Fatal(err)
+	}
+	snaps, err := s.Snapshots("Standard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 4 recent + 1 for the hour two days ago + 1 for the day two months ago
+	if len(snaps) != 6 {
+		t.Errorf("expected 6 snapshots after compaction, got %d", len(snaps))
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the league file after compaction, got %d entries", len(entries))
+	}
+}
+
+func TestCompactRecentOverLimit(t *testing.T) {
+	s := New(t.TempDir())
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := make([]domain.GemPrice, 20000)
+	for i := range prices {
+		prices[i] = domain.GemPrice{Name: fmt.Sprintf("Gem %05d", i), ChaosValue: float64(i)}
+	}
+
+	// Five-minute polls within the last day, whose snapshots alone are over
+	// compactSize and so all survive compaction
+	at := now.Add(-time.Hour)
+	appendSnap := func() os.FileInfo {
+		t.Helper()
+		at = at.Add(5 * time.Minute)
+		if err := s.Append("Standard", at, prices); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(s.path("Standard"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+	info := appendSnap()
+	for info.Size() <= compactSize {
+		info = appendSnap()
+	}
+	if s.compacted["Standard"] == 0 {
+		t.Fatal("file over compactSize not compacted")
+	}
+
+	// Appends within compactMargin go on the end of the same file rather
+	// than rewriting it
+	next := appendSnap()
+	if !os.SameFile(info, next) {
+		t.Error("file rewritten on the next append")
+	}
+	limit := s.compacted["Standard"] + compactMargin
+	for next.Size() <= limit {
+		next = appendSnap()
+	}
+	if os.SameFile(info, next) {
+		t.Error("file not compacted again after growing by compactMargin")
+	}
+}
@@ -0,0 +1,239 @@
+// Package loader fetches leagues, wiki data and prices through the cache so
+// the TUI and the headless commands share one code path.
+package loader
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/api"
+	"github.com/ovestokke/gemcheck-tui/internal/cache"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/history"
+	"github.com/ovestokke/gemcheck-tui/internal/metrics"
+)
+
+// Default cache TTLs
+const (
+	LeagueTTL = 1 * time.Hour
+	WikiTTL   = 24 * time.Hour
+	PriceTTL  = 5 * time.Minute
+)
+
+// FallbackTTL is how long a league list from a fallback source is kept
+// before trying the GGG API again.
+const FallbackTTL = 5 * time.Minute
+
+// TTLs are how long each kind of data stays cached.
+type TTLs struct {
+	Leagues time.Duration
+	Wiki    time.Duration
+	Prices  time.Duration
+}
+
+// DefaultTTLs are the TTLs a new Loader starts with.
+var DefaultTTLs = TTLs{Leagues: LeagueTTL, Wiki: WikiTTL, Prices: PriceTTL}
+
+// Cache keys
+const (
+	KeyLeagues = "leagues"
+	KeyWiki    = "wiki"
+)
+
+// PricesKey returns the cache key for a league's prices.
+func PricesKey(league string) string {
+	return "prices:" + league
+}
+
+// Loader fetches data through a cache and records fresh prices to history.
+// It is safe for concurrent use; concurrent misses for the same key share
+// one upstream fetch.
+type Loader struct {
+	Cache   *cache.Cache
+	History *history.Store    // optional
+	Metrics *metrics.Registry // optional
+
+	mu       sync.Mutex
+	ttl      TTLs
+	inflight map[string]*call
+	recorded map[string]time.Time // by league, the last fetch appended to history
+}
+
+// call is an upstream fetch other callers can wait on.
+type call struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// New creates a loader. hist may be nil to disable price history.
+func New(c *cache.Cache, hist *history.Store) *Loader {
+	return &Loader{Cache: c, History: hist, ttl: DefaultTTLs, inflight: make(map[string]*call),
+		recorded: make(map[string]time.Time)}
+}
+
+// TTL returns the current cache TTLs.
+func (l *Loader) TTL() TTLs {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ttl
+}
+
+// SetTTL changes the cache TTLs for data fetched from now on.
+func (l *Loader) SetTTL(t TTLs) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ttl = t
+}
+
+// once runs fetch for key unless a fetch for key is already running, in
+// which case it waits for that one's result.
+func (l *Loader) once(key string, fetch func() (any, error)) (any, error) {
+	l.mu.Lock()
+	if c, ok := l.inflight[key]; ok {
+		l.mu.Unlock()
+		<-c.done
+		return c.val, c.err
+	}
+	c := &call{done: make(chan struct{})}
+	l.inflight[key] = c
+	l.mu.Unlock()
+
+	c.val, c.err = fetch()
+	close(c.done)
+
+	l.mu.Lock()
+	delete(l.inflight, key)
+	l.mu.Unlock()
+	return c.val, c.err
+}
+
+// observe records an upstream fetch that began at start.
+func (l *Loader) observe(upstream string, start time.Time, err error) {
+	if l.Metrics != nil {
+		l.Metrics.ObserveFetch(upstream, time.Since(start), err)
+	}
+}
+
+// Leagues returns the active leagues, from memory when fresh. When the GGG
+// API fails it falls back to poe.ninja's league list, then to the last list
+// saved to disk however old, and only fails if all three do. Fallback lists
+// are kept for FallbackTTL so GGG is retried soon.
+func (l *Loader) Leagues() ([]domain.League, error) {
+	if data, ok := l.Cache.Get(KeyLeagues); ok {
+		if leagues, ok := data.([]domain.League); ok {
+			return leagues, nil
+		}
+	}
+	v, err := l.once(KeyLeagues, func() (any, error) {
+		start := time.Now()
+		leagues, err := api.FetchLeagues()
+		l.observe(metrics.Leagues, start, err)
+		if err == nil {
+			l.Cache.SaveToDisk(KeyLeagues, leagues, l.TTL().Leagues)
+			l.Cache.Set(KeyLeagues, leagues, l.TTL().Leagues)
+			return leagues, nil
+		}
+
+		start = time.Now()
+		leagues, ninjaErr := api.FetchNinjaLeagues()
+		l.observe(metrics.Ninja, start, ninjaErr)
+		if ninjaErr != nil && !l.Cache.LoadStaleFromDisk(KeyLeagues, &leagues) {
+			return nil, errors.Join(err, ninjaErr, errors.New("no saved league list"))
+		}
+		l.Cache.Set(KeyLeagues, leagues, min(FallbackTTL, l.TTL().Leagues))
+		return leagues, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]domain.League), nil
+}
+
+// Wiki returns the transfigured gem lists, from memory or disk when fresh.
+func (l *Loader) Wiki() (*domain.WikiData, error) {
+	if data, ok := l.Cache.Get(KeyWiki); ok {
+		if wiki, ok := data.(*domain.WikiData); ok {
+			return wiki, nil
+		}
+	}
+	v, err := l.once(KeyWiki, func() (any, error) {
+		var wiki domain.WikiData
+		if l.Cache.LoadFromDisk(KeyWiki, &wiki) {
+			l.Cache.Set(KeyWiki, &wiki, l.TTL().Wiki)
+			return &wiki, nil
+		}
+		start := time.Now()
+		w, err := api.FetchWikiData()
+		l.observe(metrics.Wiki, start, err)
+		if err != nil {
+			return nil, err
+		}
+		l.Cache.SaveToDisk(KeyWiki, w, l.TTL().Wiki)
+		l.Cache.Set(KeyWiki, w, l.TTL().Wiki)
+		return w, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*domain.WikiData), nil
+}
+
+// Prices returns poe.ninja gem prices for a league, from memory when fresh.
+// Freshly fetched prices are appended to the league's history.
+func (l *Loader) Prices(league string) ([]domain.GemPrice, error) {
+	key := PricesKey(league)
+	if data, ok := l.Cache.Get(key); ok {
+		if prices, ok := data.([]domain.GemPrice); ok {
+			return prices, nil
+		}
+	}
+	v, err := l.once(key, func() (any, error) {
+		start := time.Now()
+		prices, err := api.FetchGemPrices(league)
+		l.observe(metrics.Ninja, start, err)
+		if err != nil {
+			return nil, err
+		}
+		l.Cache.Set(key, prices, l.TTL().Prices)
+		if l.History != nil {
+			// History is best-effort; a full disk shouldn't break pricing
+			at := time.Now()
+			l.History.Append(league, at, prices)
+			l.mu.Lock()
+			l.recorded[league] = at
+			l.mu.Unlock()
+		}
+		return prices, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]domain.GemPrice), nil
+}
+
+// Recorded returns when the league's last fetched prices were appended to
+// history, zero if none were this run.
+func (l *Loader) Recorded(league string) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.recorded[league]
+}
+
+// Process loads wiki data and prices for a league and runs domain.ProcessGems.
+func (l *Loader) Process(league string, topN int, excluded domain.Exclusions) (domain.ProcessedResult, error) {
+	wiki, err := l.Wiki()
+	if err != nil {
+		return domain.ProcessedResult{}, err
+	}
+	prices, err := l.Prices(league)
+	if err != nil {
+		return domain.ProcessedResult{}, err
+	}
+	result := domain.ProcessGems(*wiki, prices, topN, excluded)
+	if l.Metrics != nil {
+		l.Metrics.ObserveResult(league, result)
+	}
+	return result, nil
+}
@@ -0,0 +1,207 @@
+// Package metrics collects pool EV, gem prices, upstream fetch timings and
+// cache statistics, and serves them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/cache"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// Upstream names used as the upstream label.
+const (
+	Ninja   = "ninja"
+	Wiki    = "wiki"
+	Leagues = "leagues"
+)
+
+// latencyBuckets are the fetch duration histogram bounds, in seconds.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type upstream struct {
+	requests uint64
+	errors   uint64
+	buckets  []uint64 // cumulative counts per latencyBuckets bound
+	sum      float64
+}
+
+type gemPrice struct {
+	color domain.GemColor
+	price float64
+}
+
+// Registry holds the current metric values. The zero value is not usable;
+// call New.
+type Registry struct {
+	cache *cache.Cache
+
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+	pools     map[string]map[domain.GemColor]float64 // league -> color -> pool EV
+	gems      map[string]map[string]gemPrice         // league -> bingo gem -> price
+}
+
+// New creates a registry that also reports c's hit and miss counts.
+func New(c *cache.Cache) *Registry {
+	return &Registry{
+		cache:     c,
+		upstreams: make(map[string]*upstream),
+		pools:     make(map[string]map[domain.GemColor]float64),
+		gems:      make(map[string]map[string]gemPrice),
+	}
+}
+
+// ObserveFetch records one upstream fetch.
+func (r *Registry) ObserveFetch(name string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.upstreams[name]
+	if !ok {
+		u = &upstream{buckets: make([]uint64, len(latencyBuckets))}
+		r.upstreams[name] = u
+	}
+	u.requests++
+	if err != nil {
+		u.errors++
+	}
+	secs := d.Seconds()
+	u.sum += secs
+	for i, bound := range latencyBuckets {
+		if secs <= bound {
+			u.buckets[i]++
+		}
+	}
+}
+
+// ObserveResult records a league's pool EVs and the prices of each pool's
+// bingo gems, replacing what was recorded for the league before.
+func (r *Registry) ObserveResult(league string, result domain.ProcessedResult) {
+	pools := make(map[domain.GemColor]float64)
+	gems := make(map[string]gemPrice)
+	for _, c := range domain.AllColors {
+		stats := result.ColorStats[c]
+		pools[c] = stats.PoolEV
+		for _, b := range stats.Bingo {
+			gems[b.Name] = gemPrice{color: c, price: b.SellPrice}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pools[league] = pools
+	r.gems[league] = gems
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	r.mu.Lock()
+
+	header(&b, "gemcheck_pool_ev_chaos", "gauge", "Best-of-3 font pool EV in chaos.")
+	for _, league := range sortedKeys(r.pools) {
+		for _, c := range domain.AllColors {
+			if ev, ok := r.pools[league][c]; ok {
+				sample(&b, "gemcheck_pool_ev_chaos", ev, "league", league, "color", string(c))
+			}
+		}
+	}
+
+	header(&b, "gemcheck_gem_price_chaos", "gauge", "Price of each pool's top bingo gems in chaos.")
+	for _, league := range sortedKeys(r.gems) {
+		for _, gem := range sortedKeys(r.gems[league]) {
+			p := r.gems[league][gem]
+			sample(&b, "gemcheck_gem_price_chaos", p.price, "league", league, "color", string(p.color), "gem", gem)
+		}
+	}
+
+	header(&b, "gemcheck_upstream_requests_total", "counter", "Upstream fetches.")
+	for _, name := range sortedKeys(r.upstreams) {
+		sample(&b, "gemcheck_upstream_requests_total", float64(r.upstreams[name].requests), "upstream", name)
+	}
+	header(&b, "gemcheck_upstream_errors_total", "counter", "Failed upstream fetches.")
+	for _, name := range sortedKeys(r.upstreams) {
+		sample(&b, "gemcheck_upstream_errors_total", float64(r.upstreams[name].errors), "upstream", name)
+	}
+	header(&b, "gemcheck_upstream_duration_seconds", "histogram", "Upstream fetch latency.")
+	for _, name := range sortedKeys(r.upstreams) {
+		u := r.upstreams[name]
+		for i, bound := range latencyBuckets {
+			sample(&b, "gemcheck_upstream_duration_seconds_bucket", float64(u.buckets[i]),
+				"upstream", name, "le", formatFloat(bound))
+		}
+		sample(&b, "gemcheck_upstream_duration_seconds_bucket", float64(u.requests), "upstream", name, "le", "+Inf")
+		sample(&b, "gemcheck_upstream_duration_seconds_sum", u.sum, "upstream", name)
+		sample(&b, "gemcheck_upstream_duration_seconds_count", float64(u.requests), "upstream", name)
+	}
+	r.mu.Unlock()
+
+	if r.cache != nil {
+		stats := r.cache.Stats()
+		keys := sortedKeys(stats)
+		header(&b, "gemcheck_cache_hits_total", "counter", "Cache lookups that found a fresh entry.")
+		for _, k := range keys {
+			sample(&b, "gemcheck_cache_hits_total", float64(stats[k].Hits), "key", k, "layer", "memory")
+			if s := stats[k]; s.DiskHits+s.DiskMisses > 0 {
+				sample(&b, "gemcheck_cache_hits_total", float64(s.DiskHits), "key", k, "layer", "disk")
+			}
+		}
+		header(&b, "gemcheck_cache_misses_total", "counter", "Cache lookups that found nothing fresh.")
+		for _, k := range keys {
+			sample(&b, "gemcheck_cache_misses_total", float64(stats[k].Misses), "key", k, "layer", "memory")
+			if s := stats[k]; s.DiskHits+s.DiskMisses > 0 {
+				sample(&b, "gemcheck_cache_misses_total", float64(s.DiskMisses), "key", k, "layer", "disk")
+			}
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func header(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one line; labels are name, value pairs.
+func sample(b *strings.Builder, name string, v float64, labels ...string) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, `%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteString(" " + formatFloat(v) + "\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/cache"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func TestWriteTo(t *testing.T) {
+	c := cache.New("")
+	c.Set("prices:Settlers", 1, time.Minute)
+	c.Get("prices:Settlers")
+	c.Get("prices:Settlers")
+	c.Get("leagues")
+
+	r := New(c)
+	r.ObserveFetch(Ninja, 200*time.Millisecond, nil)
+	r.ObserveFetch(Ninja, 3*time.Second, errors.New("HTTP 503"))
+	r.ObserveResult(`Settlers "HC"`, domain.ProcessGems(
+		domain.WikiData{TransfigGems: map[domain.GemColor][]string{
+			domain.Red: {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+		}},
+		[]domain.GemPrice{
+			{Name: "Boneshatter of Carnage", ChaosValue: 100},
+			{Name: "Boneshatter of Complex Trauma", ChaosValue: 50},
+		},
+		1, nil,
+	))
+
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, line := range []string{
+		"# TYPE gemcheck_pool_ev_chaos gauge",
+		`gemcheck_pool_ev_chaos{league="Settlers \"HC\"",color="r"} 93.75`,
+		`gemcheck_pool_ev_chaos{league="Settlers \"HC\"",color="g"} 0`,
+		`gemcheck_gem_price_chaos{league="Settlers \"HC\"",color="r",gem="Boneshatter of Carnage"} 100`,
+		`gemcheck_upstream_requests_total{upstream="ninja"} 2`,
+		`gemcheck_upstream_errors_total{upstream="ninja"} 1`,
+		`gemcheck_upstream_duration_seconds_bucket{upstream="ninja",le="0.25"} 1`,
+		`gemcheck_upstream_duration_seconds_bucket{upstream="ninja",le="5"} 2`,
+		`gemcheck_upstream_duration_seconds_bucket{upstream="ninja",le="+Inf"} 2`,
+		`gemcheck_upstream_duration_seconds_sum{upstream="ninja"} 3.2`,
+		`gemcheck_cache_hits_total{key="prices:Settlers",layer="memory"} 2`,
+		`gemcheck_cache_misses_total{key="leagues",layer="memory"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing line %s", line)
+		}
+	}
+	if strings.Contains(out, "Complex Trauma") {
+		t.Error("non-bingo gem exported with topN 1")
+	}
+}
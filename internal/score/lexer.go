@@ -0,0 +1,95 @@
+package score
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string // lower-cased for identifiers
+	num  float64
+	pos  int // 1-based column
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// twoCharOps must be checked before their one-character prefixes.
+var twoCharOps = []string{"<=", ">=", "==", "!=", "&&", "||"}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case c == ',':
+			toks = append(toks, token{kind: tokComma, text: ",", pos: pos})
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("invalid number %q", src[i:j])}
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[i:j], num: n, pos: pos})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: strings.ToLower(src[i:j]), pos: pos})
+			i = j
+		default:
+			op := ""
+			for _, two := range twoCharOps {
+				if strings.HasPrefix(src[i:], two) {
+					op = two
+					break
+				}
+			}
+			if op == "" && strings.ContainsRune("+-*/%<>!", c) {
+				op = string(c)
+			}
+			if op == "" {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: pos})
+			i += len(op)
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: len(src) + 1})
+	return toks, nil
+}
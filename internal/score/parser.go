@@ -0,0 +1,410 @@
+package score
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// Grammar, lowest precedence first:
+//
+//	expr  := or [ "if" or [ "else" expr ] ]
+//	or    := and { ("or" | "||") and }
+//	and   := not { ("and" | "&&") not }
+//	not   := ("not" | "!") not | cmp
+//	cmp   := sum [ ("<" | "<=" | ">" | ">=" | "==" | "!=") sum ]
+//	sum   := term { ("+" | "-") term }
+//	term  := unary { ("*" | "/" | "%") unary }
+//	unary := "-" unary | primary
+//	primary := number | field | func "(" expr { "," expr } ")" | "(" expr ")"
+type parser struct {
+	toks  []token
+	i     int
+	depth int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is an operator or keyword in ops.
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.i++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseExpr() (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, &Error{Pos: p.peek().pos, Msg: "expression nested too deeply"}
+	}
+
+	then, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("if"); !ok {
+		return then, nil
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	var els node
+	if _, ok := p.accept("else"); ok {
+		if els, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return condNode{then: then, cond: cond, els: els}, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or", "||"); !ok {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "or", l: l, r: r}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and", "&&"); !ok {
+			return l, nil
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "and", l: l, r: r}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("not", "!"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "not", x: x}, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *parser) parseCmp() (node, error) {
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return l, nil
+	}
+	r, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: op, l: l, r: r}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	l, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return numNode(t.num), nil
+	case tokLParen:
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, &Error{Pos: r.pos, Msg: fmt.Sprintf("expected ')', got %s", r)}
+		}
+		return x, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t)
+		}
+		if _, ok := fields[t.text]; !ok {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unknown field %q", t.text)}
+		}
+		return fieldNode(t.text), nil
+	}
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	arity, ok := funcs[name.text]
+	if !ok {
+		return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("unknown function %q", name.text)}
+	}
+	p.next() // (
+
+	var args []node
+	if p.peek().kind != tokRParen {
+		for {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, x)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if r := p.next(); r.kind != tokRParen {
+		return nil, &Error{Pos: r.pos, Msg: fmt.Sprintf("expected ')' or ',', got %s", r)}
+	}
+	if len(args) < arity.min || (arity.max > 0 && len(args) > arity.max) {
+		return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("%s() takes %s", name.text, arity)}
+	}
+	return callNode{fn: name.text, args: args}, nil
+}
+
+type arity struct{ min, max int } // max 0 means variadic
+
+func (a arity) String() string {
+	switch {
+	case a.max == 0:
+		return fmt.Sprintf("at least %d argument(s)", a.min)
+	case a.min == a.max:
+		return fmt.Sprintf("%d argument(s)", a.min)
+	}
+	return fmt.Sprintf("%d to %d arguments", a.min, a.max)
+}
+
+var funcs = map[string]arity{
+	"min":  {1, 0},
+	"max":  {1, 0},
+	"abs":  {1, 1},
+	"sqrt": {1, 1},
+	"log":  {1, 1},
+}
+
+// Evaluation. Every node reports ok=false when it declines to score, and
+// that propagates up through any node that depends on it.
+
+type node interface {
+	eval(e domain.GemEntry) (float64, bool)
+}
+
+type numNode float64
+
+func (n numNode) eval(domain.GemEntry) (float64, bool) { return float64(n), true }
+
+type fieldNode string
+
+func (n fieldNode) eval(e domain.GemEntry) (float64, bool) { return fields[string(n)](e), true }
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n unaryNode) eval(e domain.GemEntry) (float64, bool) {
+	v, ok := n.x.eval(e)
+	if !ok {
+		return 0, false
+	}
+	if n.op == "not" {
+		return boolVal(v == 0), true
+	}
+	return -v, true
+}
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+func (n binaryNode) eval(e domain.GemEntry) (float64, bool) {
+	l, ok := n.l.eval(e)
+	if !ok {
+		return 0, false
+	}
+	// Short-circuit boolean operators
+	switch {
+	case n.op == "and" && l == 0:
+		return 0, true
+	case n.op == "or" && l != 0:
+		return 1, true
+	}
+	r, ok := n.r.eval(e)
+	if !ok {
+		return 0, false
+	}
+	switch n.op {
+	case "+":
+		return l + r, true
+	case "-":
+		return l - r, true
+	case "*":
+		return l * r, true
+	case "/":
+		if r == 0 {
+			return 0, false
+		}
+		return l / r, true
+	case "%":
+		if r == 0 {
+			return 0, false
+		}
+		return math.Mod(l, r), true
+	case "<":
+		return boolVal(l < r), true
+	case "<=":
+		return boolVal(l <= r), true
+	case ">":
+		return boolVal(l > r), true
+	case ">=":
+		return boolVal(l >= r), true
+	case "==":
+		return boolVal(l == r), true
+	case "!=":
+		return boolVal(l != r), true
+	case "and", "or":
+		return boolVal(r != 0), true
+	}
+	return 0, false
+}
+
+type condNode struct {
+	then, cond, els node // els is nil when there is no else branch
+}
+
+func (n condNode) eval(e domain.GemEntry) (float64, bool) {
+	c, ok := n.cond.eval(e)
+	if !ok {
+		return 0, false
+	}
+	if c != 0 {
+		return n.then.eval(e)
+	}
+	if n.els == nil {
+		return 0, false
+	}
+	return n.els.eval(e)
+}
+
+type callNode struct {
+	fn   string
+	args []node
+}
+
+func (n callNode) eval(e domain.GemEntry) (float64, bool) {
+	vals := make([]float64, len(n.args))
+	for i, a := range n.args {
+		v, ok := a.eval(e)
+		if !ok {
+			return 0, false
+		}
+		vals[i] = v
+	}
+	switch n.fn {
+	case "min":
+		m := vals[0]
+		for _, v := range vals[1:] {
+			m = math.Min(m, v)
+		}
+		return m, true
+	case "max":
+		m := vals[0]
+		for _, v := range vals[1:] {
+			m = math.Max(m, v)
+		}
+		return m, true
+	case "abs":
+		return math.Abs(vals[0]), true
+	case "sqrt":
+		return math.Sqrt(vals[0]), true
+	case "log":
+		return math.Log(vals[0]), true
+	}
+	return 0, false
+}
+
+func boolVal(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+// Package score implements a small expression language for ranking gem
+// entries, e.g. "ev - basecost - 5 if mincount > 10".
+//
+// Expressions are arithmetic (+ - * / %), comparisons (< <= > >= == !=),
+// boolean logic (and, or, not, or && || !) and conditionals
+// ("a if cond else b"), over the fields listed by Fields and the functions
+// min, max, abs, sqrt and log. Identifiers are case-insensitive. Booleans are
+// 1 or 0.
+//
+// An expression can decline to score an entry: a conditional without an else
+// branch whose condition is false, a division by zero or a non-finite result
+// all yield no score, and such entries rank last.
+package score
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// Limits that keep user input cheap to evaluate.
+const (
+	maxLength = 512
+	maxDepth  = 32
+)
+
+// fields maps identifiers to the GemEntry values they expose.
+var fields = map[string]func(domain.GemEntry) float64{
+	"ev":         func(e domain.GemEntry) float64 { return e.EV },
+	"net":        netProfit,
+	"basecost":   func(e domain.GemEntry) float64 { return e.BaseCost },
+	"variants":   func(e domain.GemEntry) float64 { return float64(e.VariantCount) },
+	"listed":     func(e domain.GemEntry) float64 { return float64(e.ListedCount()) },
+	"unlisted":   func(e domain.GemEntry) float64 { return float64(e.VariantCount - e.ListedCount()) },
+	"mincount":   func(e domain.GemEntry) float64 { return float64(e.MinCount()) },
+	"maxcount":   func(e domain.GemEntry) float64 { return float64(e.MaxCount()) },
+	"totalcount": func(e domain.GemEntry) float64 { return float64(e.TotalCount()) },
+	"minprice":   domain.GemEntry.MinPrice,
+	"maxprice":   domain.GemEntry.MaxPrice,
+	"median":     domain.GemEntry.Median,
+	"stddev":     domain.GemEntry.StdDev,
+	"bestof":     func(e domain.GemEntry) float64 { return e.BestOfEV(domain.FontDraws) },
+	"liquidity":  domain.GemEntry.LiquidityEV,
+	"risk":       domain.GemEntry.RiskAdjustedEV,
+}
+
+// netProfit is -Inf for a gem whose base gem is unpriced, so a score built
+// on it leaves the gem unscored rather than treating the base gem as free.
+func netProfit(e domain.GemEntry) float64 {
+	if !e.BasePriced {
+		return math.Inf(-1)
+	}
+	return e.NetProfit()
+}
+
+// Fields returns the identifiers an expression may reference, sorted.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Error is a parse error at a 1-based column of the source.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos, e.Msg)
+}
+
+// Expr is a parsed scoring expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse compiles an expression, reporting the column of the first error.
+func Parse(src string) (*Expr, error) {
+	if len(src) > maxLength {
+		return nil, &Error{Pos: maxLength + 1, Msg: fmt.Sprintf("expression longer than %d characters", maxLength)}
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source the expression was parsed from.
+func (x *Expr) String() string { return x.src }
+
+// Eval scores an entry. ok is false when the expression declines to score it.
+func (x *Expr) Eval(e domain.GemEntry) (v float64, ok bool) {
+	v, ok = x.root.eval(e)
+	if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// Strategy wraps the expression as a ranking strategy. Unscored entries get
+// -Inf so they sort last.
+func (x *Expr) Strategy(name string) domain.RankStrategy {
+	return domain.RankStrategy{
+		Name: name,
+		Score: func(e domain.GemEntry) float64 {
+			if v, ok := x.Eval(e); ok {
+				return v
+			}
+			return math.Inf(-1)
+		},
+	}
+}
+
+// Strategies parses named expressions into ranking strategies sorted by name.
+// An expression that fails to parse still gets a strategy, scoring nothing, so
+// it can be selected and its error from errs shown alongside.
+func Strategies(defs map[string]string) (strategies []domain.RankStrategy, errs map[string]error) {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs = make(map[string]error)
+	for _, name := range names {
+		x, err := Parse(defs[name])
+		if err != nil {
+			errs[name] = err
+			strategies = append(strategies, domain.RankStrategy{
+				Name:  name,
+				Score: func(domain.GemEntry) float64 { return math.Inf(-1) },
+			})
+			continue
+		}
+		strategies = append(strategies, x.Strategy(name))
+	}
+	return strategies, errs
+}
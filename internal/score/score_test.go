@@ -0,0 +1,120 @@
+package score
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+var testEntry = domain.GemEntry{
+	BaseName:     "Boneshatter",
+	EV:           75,
+	BaseCost:     10,
+	BasePriced:   true,
+	VariantCount: 2,
+	Variants: []domain.GemVariantResult{
+		{Name: "Boneshatter of Carnage", SellPrice: 100, Prob: 0.5, Count: 12, Listed: true},
+		{Name: "Boneshatter of Complex Trauma", SellPrice: 50, Prob: 0.5, Count: 30, Listed: true},
+	},
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want float64
+		ok   bool
+	}{
+		{"ev", 75, true},
+		{"EV - BaseCost", 65, true},
+		{"ev - basecost - 5 if minCount > 10", 60, true},
+		{"ev - basecost - 5 if minCount > 20", 0, false},
+		{"ev if mincount > 20 else 0", 0, true},
+		{"2 + 3 * 4", 14, true},
+		{"(2 + 3) * 4", 20, true},
+		{"-maxprice + 1", -99, true},
+		{"10 % 4", 2, true},
+		{"max(minprice, 60, 7)", 60, true},
+		{"min(abs(-3), sqrt(16))", 3, true},
+		{"listed == 2 and not unlisted", 1, true},
+		{"variants > 5 || totalcount >= 42", 1, true},
+		{"ev / 0", 0, false},
+		{"ev / (unlisted or 0)", 0, false},
+		{"log(0)", 0, false},
+	}
+	for _, tt := range tests {
+		x, err := Parse(tt.src)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.src, err)
+			continue
+		}
+		got, ok := x.Eval(testEntry)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Eval(%q) = %v, %v; want %v, %v", tt.src, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+	}{
+		{"ev +", 5},
+		{"ev - price", 6},
+		{"(ev", 4},
+		{"ev $ 2", 4},
+		{"pow(ev, 2)", 1},
+		{"abs(ev, 2)", 1},
+		{"ev ev", 4},
+		{"1..2", 1},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%q): expected *Error, got %v", tt.src, err)
+			continue
+		}
+		if perr.Pos != tt.pos {
+			t.Errorf("Parse(%q): error at col %d, want %d (%v)", tt.src, perr.Pos, tt.pos, perr)
+		}
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	strategies, errs := Strategies(map[string]string{
+		"liquid": "ev - basecost if mincount > 10",
+		"broken": "ev +",
+	})
+	if len(strategies) != 2 {
+		t.Fatalf("expected 2 strategies, got %d", len(strategies))
+	}
+	if strategies[0].Name != "broken" || strategies[1].Name != "liquid" {
+		t.Errorf("strategies not sorted by name: %q, %q", strategies[0].Name, strategies[1].Name)
+	}
+	if errs["broken"] == nil || errs["liquid"] != nil {
+		t.Errorf("unexpected errs: %v", errs)
+	}
+	if got := strategies[1].Score(testEntry); got != 65 {
+		t.Errorf("liquid score = %v, want 65", got)
+	}
+	if got := strategies[0].Score(testEntry); !math.IsInf(got, -1) {
+		t.Errorf("broken score = %v, want -Inf", got)
+	}
+}
+
+func TestEvalUnpricedBase(t *testing.T) {
+	e := testEntry
+	e.BasePriced, e.BaseCost = false, 0
+	for src, want := range map[string]bool{"net": false, "net + 5": false, "ev - basecost": true} {
+		x, err := Parse(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := x.Eval(e); ok != want {
+			t.Errorf("Eval(%q) ok = %v, want %v", src, ok, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package server exposes EV data over HTTP so several users can share one
+// cached backend instead of each hitting poe.ninja and the wiki.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/export"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+)
+
+// errNoLeague is returned for league IDs not in the active league list.
+var errNoLeague = errors.New("unknown league")
+
+// Server serves the JSON API.
+type Server struct {
+	loader   *loader.Loader
+	excluded domain.Exclusions
+	topN     int
+	mux      *http.ServeMux
+}
+
+// New creates a server that loads through l, leaves excluded gems out of
+// the pool math and lists topN bingo gems per pool.
+func New(l *loader.Loader, excluded domain.Exclusions, topN int) *Server {
+	s := &Server{loader: l, excluded: excluded, topN: topN, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /leagues", s.handleLeagues)
+	s.mux.HandleFunc("GET /leagues/{id}/ev", s.handleEV)
+	s.mux.HandleFunc("GET /leagues/{id}/pools", s.handlePools)
+	s.mux.HandleFunc("GET /leagues/{id}/gems/{base}", s.handleGem)
+	if l.Metrics != nil {
+		s.mux.Handle("GET /metrics", l.Metrics)
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// league is the /leagues item.
+type league struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Kind     string     `json:"kind"`
+	Realm    string     `json:"realm,omitempty"`
+	StartAt  *time.Time `json:"start_at,omitempty"`
+	EndAt    *time.Time `json:"end_at,omitempty"`
+	Hardcore bool       `json:"hardcore"`
+	SSF      bool       `json:"ssf"`
+	Event    bool       `json:"event"`
+}
+
+func (s *Server) handleLeagues(w http.ResponseWriter, r *http.Request) {
+	leagues, err := s.loader.Leagues()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	out := make([]league, len(leagues))
+	for i, l := range leagues {
+		out[i] = league{
+			ID: l.ID, Name: l.Text, Kind: l.Kind(), Realm: l.Realm,
+			Hardcore: l.Hardcore, SSF: l.SSF, Event: l.Event,
+		}
+		if !l.StartAt.IsZero() {
+			out[i].StartAt = &l.StartAt
+		}
+		if !l.EndAt.IsZero() {
+			out[i].EndAt = &l.EndAt
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleEV(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.result(w, r)
+	if !ok {
+		return
+	}
+	if c := r.URL.Query().Get("color"); c != "" {
+		gc, err := domain.ParseColor(c)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		res = res.ForColor(gc)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handlePools(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.result(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Pools)
+}
+
+func (s *Server) handleGem(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.result(w, r)
+	if !ok {
+		return
+	}
+	gem, ok := res.Gem(r.PathValue("base"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("no transfigured gem with that base name"))
+		return
+	}
+	writeJSON(w, http.StatusOK, gem)
+}
+
+// result prices the league in the request path, writing an error response
+// and returning false on failure.
+func (s *Server) result(w http.ResponseWriter, r *http.Request) (export.Result, bool) {
+	id := r.PathValue("id")
+	leagues, err := s.loader.Leagues()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return export.Result{}, false
+	}
+	known := false
+	for _, l := range leagues {
+		known = known || l.ID == id
+	}
+	if !known {
+		writeError(w, http.StatusNotFound, errNoLeague)
+		return export.Result{}, false
+	}
+
+	result, err := s.loader.Process(id, s.topN, s.excluded)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return export.Result{}, false
+	}
+	return export.NewResult(id, time.Now(), result), true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ovestokke/gemcheck-tui/internal/cache"
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/export"
+	"github.com/ovestokke/gemcheck-tui/internal/loader"
+	"github.com/ovestokke/gemcheck-tui/internal/metrics"
+)
+
+// newTestServer returns a server whose loader is pre-filled so no request
+// reaches upstream.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	c := cache.New("")
+	c.Set(loader.KeyLeagues, []domain.League{{ID: "Settlers", Text: "Settlers"}}, loader.LeagueTTL)
+	c.Set(loader.KeyWiki, &domain.WikiData{TransfigGems: map[domain.GemColor][]string{
+		domain.Red:  {"Boneshatter of Carnage", "Boneshatter of Complex Trauma"},
+		domain.Blue: {"Arc of Surging"},
+	}}, loader.WikiTTL)
+	c.Set(loader.PricesKey("Settlers"), []domain.GemPrice{
+		{Name: "Boneshatter", ChaosValue: 5},
+		{Name: "Boneshatter of Carnage", ChaosValue: 100, Count: 12},
+		{Name: "Boneshatter of Complex Trauma", ChaosValue: 50, Count: 30},
+		{Name: "Arc of Surging", ChaosValue: 20},
+	}, loader.PriceTTL)
+
+	l := loader.New(c, nil)
+	l.Metrics = metrics.New(c)
+	srv := httptest.NewServer(New(l, nil, domain.DefaultTopN))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, srv *httptest.Server, path string, wantStatus int, v any) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s: status %d, want %d", path, resp.StatusCode, wantStatus)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s: Content-Type %q", path, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	srv := newTestServer(t)
+
+	var leagues []league
+	get(t, srv, "/leagues", http.StatusOK, &leagues)
+	if len(leagues) != 1 || leagues[0].ID != "Settlers" || leagues[0].Kind != domain.KindSoftcore {
+		t.Errorf("leagues = %+v", leagues)
+	}
+
+	var ev export.Result
+	get(t, srv, "/leagues/Settlers/ev", http.StatusOK, &ev)
+	if ev.Schema != export.SchemaVersion || ev.League != "Settlers" || len(ev.Gems) != 2 || len(ev.Pools) != 3 {
+		t.Errorf("ev: schema %d, league %q, %d gems, %d pools", ev.Schema, ev.League, len(ev.Gems), len(ev.Pools))
+	}
+
+	get(t, srv, "/leagues/Settlers/ev?color=b", http.StatusOK, &ev)
+	if len(ev.Gems) != 1 || ev.Gems[0].Base != "Arc" || len(ev.Pools) != 1 {
+		t.Errorf("ev?color=b: %+v", ev.Gems)
+	}
+
+	var pools []export.Pool
+	get(t, srv, "/leagues/Settlers/pools", http.StatusOK, &pools)
+	if len(pools) != 3 || pools[0].Color != "r" || pools[0].EV <= 0 {
+		t.Errorf("pools = %+v", pools)
+	}
+
+	var gem export.Gem
+	get(t, srv, "/leagues/Settlers/gems/boneshatter", http.StatusOK, &gem)
+	if gem.Base != "Boneshatter" || gem.EV != 75 || gem.NetProfit == nil || *gem.NetProfit != 70 || len(gem.Variants) != 2 {
+		t.Errorf("gem = %+v", gem)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	srv := newTestServer(t)
+	var pools []export.Pool
+	get(t, srv, "/leagues/Settlers/pools", http.StatusOK, &pools)
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `gemcheck_pool_ev_chaos{league="Settlers",color="r"} 93.75`) {
+		t.Errorf("pool EV missing from /metrics:\n%s", body)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := newTestServer(t)
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/leagues/Nope/ev", http.StatusNotFound},
+		{"/leagues/Settlers/gems/Cleave", http.StatusNotFound},
+		{"/leagues/Settlers/ev?color=purple", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		var body map[string]string
+		get(t, srv, tt.path, tt.status, &body)
+		if body["error"] == "" {
+			t.Errorf("GET %s: no error message", tt.path)
+		}
+	}
+}
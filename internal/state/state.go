@@ -0,0 +1,90 @@
+// Package state remembers where the TUI was left between sessions, in
+// $XDG_STATE_HOME/gemcheck/state.json.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+// State is the UI position saved on quit.
+type State struct {
+	// League is the league ID.
+	League string `json:"league,omitempty"`
+
+	// Tab is the active color tab, 0 for red.
+	Tab int `json:"tab"`
+
+	// Gem is the base name of the highlighted row.
+	Gem string `json:"gem,omitempty"`
+
+	// Rank is the name of the ranking strategy.
+	Rank string `json:"rank,omitempty"`
+
+	// Sort is the key of the gem table's sort column, and SortAsc its
+	// direction.
+	Sort    string `json:"sort,omitempty"`
+	SortAsc bool   `json:"sort_asc,omitempty"`
+
+	// Filter is the gem table's filter, picked as the named Preset if set.
+	Filter domain.GemFilter `json:"filter,omitzero"`
+	Preset string           `json:"preset,omitempty"`
+}
+
+// Path returns the location of state.json: $XDG_STATE_HOME/gemcheck, or
+// ~/.local/state/gemcheck when XDG_STATE_HOME is unset.
+func Path() (string, error) {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gemcheck", "state.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "gemcheck", "state.json"), nil
+}
+
+// Load reads the state at path. A missing file yields the zero State.
+func Load(path string) (State, error) {
+	var s State
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(b, &s)
+	return s, err
+}
+
+// Save writes s to path, creating its directory. The state is written to a
+// temporary file first, so a quit cut short can't leave half a file.
+func Save(path string, s State) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
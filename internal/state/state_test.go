@@ -0,0 +1,37 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gemcheck", "state.json")
+
+	s, err := Load(path)
+	if err != nil || s != (State{}) {
+		t.Fatalf("missing file: %+v, %v", s, err)
+	}
+
+	want := State{League: "Settlers", Tab: 2, Gem: "Boneshatter", Rank: "Net profit", Sort: "worst", SortAsc: true,
+		Filter: domain.GemFilter{MinEV: 50, AllListed: true}, Preset: "big"}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || got != want {
+		t.Errorf("round trip: got %+v, %v", got, err)
+	}
+	if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("temporary files left behind: %v", files)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("corrupt file loaded without error")
+	}
+}
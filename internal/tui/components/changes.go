@@ -0,0 +1,178 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// ChangesModel lists the biggest movers between a baseline and current prices.
+type ChangesModel struct {
+	baseline  string
+	baseIdx   int
+	baseCount int
+	deltas    []domain.GemDelta
+	pools     map[domain.GemColor]float64
+	message   string
+	active    bool
+	scroll    int
+	width     int
+	height    int
+}
+
+// NewChanges creates a changes popup.
+func NewChanges() ChangesModel {
+	return ChangesModel{}
+}
+
+func (m *ChangesModel) SetSize(w, h int) { m.width = w; m.height = h }
+func (m ChangesModel) Active() bool      { return m.active }
+
+// Show displays deltas against the baseline at idx of count baselines.
+func (m *ChangesModel) Show(baseline string, idx, count int, deltas []domain.GemDelta, pools map[domain.GemColor]float64) {
+	m.baseline = baseline
+	m.baseIdx = idx
+	m.baseCount = count
+	m.deltas = deltas
+	m.pools = pools
+	m.message = ""
+	m.active = true
+	m.scroll = 0
+}
+
+// ShowMessage opens the popup with a note instead of deltas, e.g. when there
+// is nothing to compare against yet.
+func (m *ChangesModel) ShowMessage(baseline string, idx, count int, msg string) {
+	m.Show(baseline, idx, count, nil, nil)
+	m.message = msg
+}
+
+// Hide closes the changes popup.
+func (m *ChangesModel) Hide() {
+	m.active = false
+	m.deltas = nil
+}
+
+func (m ChangesModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ChangesModel) Update(msg tea.Msg) (ChangesModel, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "c":
+			m.Hide()
+		case "up", "k":
+			if m.scroll > 0 {
+				m.scroll--
+			}
+		case "down", "j":
+			if m.scroll < len(m.deltas)-1 {
+				m.scroll++
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m ChangesModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	popupWidth := min(80, m.width-4)
+	innerWidth := popupWidth - 6
+
+	var b strings.Builder
+	b.WriteString(tui.StyleTitle.Render("Changes since "+m.baseline) + "\n")
+	b.WriteString(tui.StyleHeaderDivider.Render(strings.Repeat("─", innerWidth)) + "\n")
+
+	if m.message != "" {
+		b.WriteString(tui.StyleSubtle.Render(m.message) + "\n")
+	} else {
+		var pools []string
+		for _, c := range domain.AllColors {
+			name := lipgloss.NewStyle().Foreground(tui.ColorForGem(string(c))).Render(c.Label())
+			pools = append(pools, name+" "+renderDelta(m.pools[c]))
+		}
+		b.WriteString("Pool EV: " + strings.Join(pools, "  ") + "\n\n")
+
+		if len(m.deltas) == 0 {
+			b.WriteString(tui.StyleSubtle.Render("No gems changed") + "\n")
+		}
+
+		maxVisible := max(5, (m.height-14)/2)
+		end := min(len(m.deltas), m.scroll+maxVisible)
+		for i := m.scroll; i < end; i++ {
+			d := m.deltas[i]
+			dot := lipgloss.NewStyle().Foreground(tui.ColorForGem(string(d.Color))).Render("● ")
+			name := d.BaseName
+			if len(name) > 26 {
+				name = name[:25] + "…"
+			}
+
+			var rank string
+			switch {
+			case d.Added():
+				rank = tui.StyleProb.Render("new")
+			case d.Removed():
+				rank = tui.StyleError.Render("removed")
+			case d.RankChange() != 0:
+				rank = renderRankChange(d.RankChange()) + " " +
+					tui.StyleSubtle.Render(fmt.Sprintf("#%d→#%d", d.OldRank, d.NewRank))
+			}
+
+			b.WriteString(fmt.Sprintf("%s%-26s %8s → %-8s %s  %s\n",
+				dot, name,
+				domain.FormatChaos(d.OldEV), domain.FormatChaos(d.NewEV),
+				renderDelta(d.EVChange()), rank))
+
+			var listings []string
+			if len(d.Listed) > 0 {
+				listings = append(listings, tui.StyleProb.Render(fmt.Sprintf("+%d listed", len(d.Listed))))
+			}
+			if len(d.Delisted) > 0 {
+				listings = append(listings, tui.StyleError.Render(fmt.Sprintf("-%d delisted", len(d.Delisted))))
+			}
+			if len(listings) > 0 {
+				b.WriteString("    " + strings.Join(listings, tui.Separator) + "\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(tui.StyleHelp.Render(fmt.Sprintf("esc close  ↑↓ scroll  ←→ baseline %d/%d",
+		m.baseIdx+1, max(m.baseCount, 1))))
+
+	popup := tui.StyleDetailPopup.Width(popupWidth).Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderDelta renders a chaos change with a ▲/▼ arrow.
+func renderDelta(v float64) string {
+	switch {
+	case v > 0:
+		return tui.StyleProb.Render("▲ " + domain.FormatChaosSigned(v))
+	case v < 0:
+		return tui.StyleError.Render("▼ " + domain.FormatChaosSigned(v))
+	}
+	return tui.StyleSubtle.Render("  " + domain.FormatChaos(0))
+}
+
+// renderRankChange renders places moved up (positive) or down.
+func renderRankChange(n int) string {
+	if n > 0 {
+		return tui.StyleProb.Render(fmt.Sprintf("▲%d", n))
+	}
+	return tui.StyleError.Render(fmt.Sprintf("▼%d", -n))
+}
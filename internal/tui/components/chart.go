@@ -0,0 +1,194 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/history"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// chartSeries is one line on a chart.
+type chartSeries struct {
+	Label  string
+	Color  lipgloss.Color
+	Points []history.Point
+}
+
+// brailleBits maps a dot at (x, y) within a 2x4 braille cell to its bit.
+var brailleBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+const yAxisWidth = 9
+
+// renderChart draws series as braille lines on a shared time and value axis,
+// width×height cells including the axes. The latest point of each series is
+// marked with a dot.
+func renderChart(series []chartSeries, width, height int) string {
+	plotW := max(width-yAxisWidth-1, 4)
+	plotH := max(height-1, 2)
+
+	// Axis ranges across all series
+	var tMin, tMax time.Time
+	vMin, vMax := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			if tMin.IsZero() || p.Time.Before(tMin) {
+				tMin = p.Time
+			}
+			if p.Time.After(tMax) {
+				tMax = p.Time
+			}
+			vMin = math.Min(vMin, p.Value)
+			vMax = math.Max(vMax, p.Value)
+		}
+	}
+	if math.IsInf(vMin, 1) {
+		return tui.StyleSubtle.Render("No data")
+	}
+	if vMax-vMin < 1 {
+		vMin, vMax = math.Max(0, vMin-1), vMax+1
+	}
+	span := tMax.Sub(tMin)
+
+	dotsW, dotsH := plotW*2, plotH*4
+	toDot := func(p history.Point) (int, int) {
+		x := dotsW - 1
+		if span > 0 {
+			x = int(float64(p.Time.Sub(tMin)) / float64(span) * float64(dotsW-1))
+		}
+		y := int(math.Round((vMax - p.Value) / (vMax - vMin) * float64(dotsH-1)))
+		return x, y
+	}
+
+	cells := make([][]rune, plotH)
+	colors := make([][]int, plotH)
+	for r := range cells {
+		cells[r] = make([]rune, plotW)
+		colors[r] = make([]int, plotW)
+		for c := range colors[r] {
+			colors[r][c] = -1
+		}
+	}
+	set := func(x, y, si int) {
+		if x < 0 || y < 0 || x >= dotsW || y >= dotsH {
+			return
+		}
+		cells[y/4][x/2] |= brailleBits[x%2][y%4]
+		colors[y/4][x/2] = si
+	}
+
+	marks := make(map[[2]int]int) // cell -> series index of its latest point
+	for si, s := range series {
+		for i, p := range s.Points {
+			x, y := toDot(p)
+			if i == 0 {
+				set(x, y, si)
+				continue
+			}
+			px, py := toDot(s.Points[i-1])
+			drawLine(px, py, x, y, func(x, y int) { set(x, y, si) })
+		}
+		if n := len(s.Points); n > 0 {
+			x, y := toDot(s.Points[n-1])
+			marks[[2]int{y / 4, x / 2}] = si
+		}
+	}
+
+	var b strings.Builder
+	for r := range plotH {
+		// Y labels on the top, middle and bottom rows
+		label := ""
+		switch r {
+		case 0:
+			label = domain.FormatChaos(vMax)
+		case plotH / 2:
+			label = domain.FormatChaos((vMax + vMin) / 2)
+		case plotH - 1:
+			label = domain.FormatChaos(vMin)
+		}
+		b.WriteString(tui.StyleSubtle.Render(fmt.Sprintf("%*s ", yAxisWidth-1, label)))
+		b.WriteString(tui.StyleHeaderDivider.Render("│"))
+
+		for c := range plotW {
+			if si, ok := marks[[2]int{r, c}]; ok {
+				b.WriteString(lipgloss.NewStyle().Foreground(series[si].Color).Bold(true).Render("●"))
+				continue
+			}
+			if cells[r][c] == 0 {
+				b.WriteByte(' ')
+				continue
+			}
+			ch := string(0x2800 + cells[r][c])
+			b.WriteString(lipgloss.NewStyle().Foreground(series[colors[r][c]].Color).Render(ch))
+		}
+		b.WriteByte('\n')
+	}
+
+	// X axis: first and last timestamp
+	first, last := tMin.Local().Format("Jan 02 15:04"), tMax.Local().Format("Jan 02 15:04")
+	gap := max(1, plotW-len(first)-len(last))
+	b.WriteString(strings.Repeat(" ", yAxisWidth) + "└")
+	b.WriteString(tui.StyleSubtle.Render(first + strings.Repeat(" ", gap) + last))
+	return b.String()
+}
+
+// drawLine plots a straight line between two dots (Bresenham).
+func drawLine(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// renderLegend lists each series with its latest value.
+func renderLegend(series []chartSeries) string {
+	var parts []string
+	for _, s := range series {
+		dot := lipgloss.NewStyle().Foreground(s.Color).Render("●")
+		current := "—"
+		if n := len(s.Points); n > 0 {
+			current = domain.FormatChaos(s.Points[n-1].Value)
+			if n > 1 {
+				delta := s.Points[n-1].Value - s.Points[0].Value
+				current += tui.StyleSubtle.Render(" (" + domain.FormatChaosSigned(delta) + ")")
+			}
+		}
+		parts = append(parts, dot+" "+s.Label+" "+current)
+	}
+	return strings.Join(parts, "   ")
+}
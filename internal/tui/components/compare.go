@@ -0,0 +1,176 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// CompareModel shows pool and gem EV in two leagues side by side, one color
+// at a time.
+type CompareModel struct {
+	a, b   string
+	cmp    domain.LeagueComparison
+	color  domain.GemColor
+	gems   []domain.GemComparison // cmp.Gems of color
+	active bool
+	scroll int
+	width  int
+	height int
+}
+
+// NewCompare creates a comparison popup.
+func NewCompare() CompareModel {
+	return CompareModel{}
+}
+
+func (m *CompareModel) SetSize(w, h int) { m.width = w; m.height = h }
+func (m CompareModel) Active() bool      { return m.active }
+
+// Show displays the comparison of league a against b, starting on color.
+func (m *CompareModel) Show(a, b string, cmp domain.LeagueComparison, color domain.GemColor) {
+	m.a, m.b = a, b
+	m.cmp = cmp
+	m.active = true
+	m.setColor(color)
+}
+
+// Hide closes the comparison popup.
+func (m *CompareModel) Hide() {
+	m.active = false
+	m.gems = nil
+}
+
+func (m *CompareModel) setColor(c domain.GemColor) {
+	m.color = c
+	m.gems = nil
+	for _, g := range m.cmp.Gems {
+		if g.Color == c {
+			m.gems = append(m.gems, g)
+		}
+	}
+	m.scroll = 0
+}
+
+func (m CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m CompareModel) Update(msg tea.Msg) (CompareModel, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(km, tui.Keys.Back), key.Matches(km, tui.Keys.Compare):
+		m.Hide()
+	case key.Matches(km, tui.Keys.Tab1):
+		m.setColor(domain.Red)
+	case key.Matches(km, tui.Keys.Tab2):
+		m.setColor(domain.Green)
+	case key.Matches(km, tui.Keys.Tab3):
+		m.setColor(domain.Blue)
+	case key.Matches(km, tui.Keys.NextTab):
+		for i, c := range domain.AllColors {
+			if c == m.color {
+				m.setColor(domain.AllColors[(i+1)%len(domain.AllColors)])
+				break
+			}
+		}
+	case key.Matches(km, tui.Keys.Up):
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case key.Matches(km, tui.Keys.Down):
+		if m.scroll < len(m.gems)-1 {
+			m.scroll++
+		}
+	}
+	return m, nil
+}
+
+func (m CompareModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	popupWidth := min(80, m.width-4)
+	innerWidth := popupWidth - 6
+	nameWidth := max(innerWidth-36, 12)
+	colA, colB := truncate(m.a, 10), truncate(m.b, 10)
+
+	var b strings.Builder
+	b.WriteString(tui.StyleTitle.Render(m.a+" vs "+m.b) + "\n")
+	b.WriteString(tui.StyleHeaderDivider.Render(strings.Repeat("─", innerWidth)) + "\n")
+
+	b.WriteString(tui.StyleSubtle.Render(fmt.Sprintf("%-*s %10s %10s %8s", nameWidth+2, "Pool EV", colA, colB, "ratio")) + "\n")
+	for _, c := range domain.AllColors {
+		ev := m.cmp.Pools[c]
+		name := lipgloss.NewStyle().Foreground(tui.ColorForGem(string(c))).Render(fmt.Sprintf("%-*s", nameWidth+2, c.Label()))
+		b.WriteString(fmt.Sprintf("%s %10s %10s %s\n", name,
+			domain.FormatChaos(ev[0]), domain.FormatChaos(ev[1]), renderRatio(ev[0], ev[1])))
+	}
+	b.WriteString("\n")
+
+	dot := lipgloss.NewStyle().Foreground(tui.ColorForGem(string(m.color))).Render("● ")
+	b.WriteString(tui.StyleSubtle.Render(fmt.Sprintf("  %-*s %10s %10s %8s", nameWidth, m.color.Label()+" gems, most different first", colA, colB, "ratio")) + "\n")
+	if len(m.gems) == 0 {
+		b.WriteString(tui.StyleSubtle.Render("No gems priced in either league") + "\n")
+	}
+
+	maxVisible := max(5, m.height-20)
+	end := min(len(m.gems), m.scroll+maxVisible)
+	for _, g := range m.gems[m.scroll:end] {
+		b.WriteString(fmt.Sprintf("%s%-*s %10s %10s %s\n", dot, nameWidth, truncate(g.BaseName, nameWidth),
+			formatEV(g.A), formatEV(g.B), renderRatio(g.A, g.B)))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(tui.StyleHelp.Render(fmt.Sprintf("esc close  ↑↓ scroll  1-3 color  %d/%d", min(m.scroll+1, len(m.gems)), len(m.gems))))
+
+	popup := tui.StyleDetailPopup.Width(popupWidth).Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderRatio renders b/a, green when b is worth more and red when less.
+// Anything within 10% either way is left plain.
+func renderRatio(a, b float64) string {
+	if a <= 0 || b <= 0 {
+		return tui.StyleSubtle.Render(fmt.Sprintf("%8s", "—"))
+	}
+	r := b / a
+	s := fmt.Sprintf("%8s", fmt.Sprintf("×%.2f", r))
+	switch {
+	case math.Abs(math.Log(r)) < math.Log(1.1):
+		return tui.StyleSubtle.Render(s)
+	case r > 1:
+		return tui.StyleProb.Render(s)
+	}
+	return tui.StyleError.Render(s)
+}
+
+// formatEV renders an EV, with a dash for gems unpriced in a league.
+func formatEV(v float64) string {
+	if v <= 0 {
+		return "—"
+	}
+	return domain.FormatChaos(v)
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -11,13 +11,15 @@ import (
 	"github.com/ovestokke/gemcheck-tui/internal/tui"
 )
 
-// DetailModel displays variant details for a selected gem entry.
+// DetailModel displays variant details for a selected gem entry, with a
+// cursor on one of its variants.
 type DetailModel struct {
-	entry  *domain.GemEntry
-	active bool
-	scroll int
-	width  int
-	height int
+	entry   *domain.GemEntry
+	active  bool
+	cursor  int
+	watched map[string]bool
+	width   int
+	height  int
 }
 
 // NewDetail creates a detail popup.
@@ -32,7 +34,23 @@ func (m DetailModel) Active() bool      { return m.active }
 func (m *DetailModel) Show(entry *domain.GemEntry) {
 	m.entry = entry
 	m.active = true
-	m.scroll = 0
+	m.cursor = 0
+}
+
+// SetWatched sets the watched gems, by name, marked with a star.
+func (m *DetailModel) SetWatched(watched map[string]bool) {
+	m.watched = watched
+}
+
+// Entry is the shown gem.
+func (m DetailModel) Entry() *domain.GemEntry { return m.entry }
+
+// SelectedVariant returns the variant under the cursor.
+func (m DetailModel) SelectedVariant() (domain.GemVariantResult, bool) {
+	if m.entry == nil || m.cursor >= len(m.entry.Variants) {
+		return domain.GemVariantResult{}, false
+	}
+	return m.entry.Variants[m.cursor], true
 }
 
 // Hide closes the detail popup.
@@ -55,11 +73,13 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 		case "esc", "q":
 			m.Hide()
 		case "up", "k":
-			if m.scroll > 0 {
-				m.scroll--
+			if m.cursor > 0 {
+				m.cursor--
 			}
 		case "down", "j":
-			m.scroll++
+			if m.entry != nil && m.cursor < len(m.entry.Variants)-1 {
+				m.cursor++
+			}
 		}
 	}
 	return m, nil
@@ -93,46 +113,80 @@ func (m DetailModel) View() string {
 		tui.Separator,
 		e.VariantCount))
 
-	// Variants
-	for _, v := range e.Variants {
+	// Break-even against the base gem's market price
+	if e.BaseCost > 0 {
+		net := e.NetProfit()
+		netStyle := tui.StyleProb
+		if net < 0 {
+			netStyle = tui.StyleError
+		}
+		b.WriteString(fmt.Sprintf("Base: %s  %s  Break-even base: ≤ %s  %s  Net: %s\n\n",
+			domain.FormatChaos(e.BaseCost),
+			tui.Separator,
+			domain.FormatChaos(domain.BreakEvenBaseCost(*e, 0)),
+			tui.Separator,
+			netStyle.Render(domain.FormatChaosSigned(net))))
+	}
+
+	// Variants, remembering which lines the cursor's are on
+	cursorLine := 0
+	for i, v := range e.Variants {
 		nameStyle := lipgloss.NewStyle().Foreground(tui.ColorText)
 		priceStyle := tui.PriceStyle(v.SellPrice)
 
-		if !v.Listed {
+		if !v.Listed || v.Excluded {
 			nameStyle = nameStyle.Foreground(tui.ColorOverlay0)
 			priceStyle = lipgloss.NewStyle().Foreground(tui.ColorOverlay0)
 		}
 
-		b.WriteString(fmt.Sprintf("  %s\n", nameStyle.Render(v.Name)))
+		border := "  "
+		if i == m.cursor {
+			cursorLine = strings.Count(b.String(), "\n")
+			border = lipgloss.NewStyle().Foreground(gemColor).Render("┃ ")
+			nameStyle = nameStyle.Bold(true)
+		}
+		name := nameStyle.Render(v.Name)
+		if m.watched[v.Name] {
+			name = tui.StyleWatched.Render(tui.WatchedMark) + name
+		}
+		b.WriteString(fmt.Sprintf("%s%s\n", border, name))
 
 		price := priceStyle.Render(domain.FormatChaos(v.SellPrice))
 		prob := tui.StyleProb.Render(domain.FormatPct(v.Prob))
 
 		unlisted := ""
-		if !v.Listed {
+		if v.Excluded {
+			prob = tui.StyleSubtle.Render("excluded from font")
+		} else if !v.Listed {
 			unlisted = tui.StyleSubtle.Render(" unlisted")
 		}
 
-		b.WriteString(fmt.Sprintf("    %s  %s%s\n", price, prob, unlisted))
+		// Price this variant alone would need to cover the base gem
+		target := ""
+		if e.BaseCost > 0 && e.NetProfit() < 0 && !v.Excluded {
+			if tp, err := domain.VariantTargetPrice(*e, v.Name, e.BaseCost); err == nil {
+				target = tui.StyleSubtle.Render("  break-even at " + domain.FormatChaos(tp))
+			}
+		}
+
+		b.WriteString(fmt.Sprintf("    %s  %s%s%s\n", price, prob, unlisted, target))
 	}
 
 	// Footer hint
 	b.WriteString("\n")
-	b.WriteString(tui.StyleHelp.Render("esc close  \u2191\u2193 scroll"))
+	b.WriteString(tui.StyleHelp.Render(fmt.Sprintf("esc close  \u2191\u2193 select  %s watch",
+		tui.Keys.Watch.Help().Key)))
 
 	content := b.String()
 
-	// Apply scroll by trimming lines
+	// Scroll just far enough to show both lines of the cursor's variant
 	lines := strings.Split(content, "\n")
 	maxVisible := m.height - 6
 	if maxVisible < 5 {
 		maxVisible = 5
 	}
-	if m.scroll > len(lines)-maxVisible {
-		m.scroll = max(0, len(lines)-maxVisible)
-	}
-	if m.scroll > 0 && m.scroll < len(lines) {
-		lines = lines[m.scroll:]
+	if scroll := cursorLine + 2 - maxVisible; scroll > 0 {
+		lines = lines[scroll:]
 	}
 	if len(lines) > maxVisible {
 		lines = lines[:maxVisible]
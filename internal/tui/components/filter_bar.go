@@ -0,0 +1,341 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// Filter bar keys, while the bar is open.
+var (
+	filterMinEV = key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "min EV"),
+	)
+	filterMinListed = key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "min listed"),
+	)
+	filterAllListed = key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "all listed"),
+	)
+	filterFavorites = key.NewBinding(
+		key.WithKeys("*"),
+		key.WithHelp("*", "favorites"),
+	)
+	filterPreset = key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "preset"),
+	)
+	filterSave = key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "save preset"),
+	)
+	filterClear = key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "clear"),
+	)
+)
+
+var styleChip = lipgloss.NewStyle().
+	Foreground(tui.ColorLavender).
+	Background(tui.ColorSurface0).
+	Padding(0, 1)
+
+// filterField is the value being typed into the filter bar.
+type filterField int
+
+const (
+	fieldNone filterField = iota
+	fieldMinEV
+	fieldMinListed
+	fieldPresetName
+)
+
+// FilterBarModel shows the gem table's filter as chips above it, and edits
+// the filter while open. It takes up a line only while open or filtering.
+type FilterBarModel struct {
+	filter  domain.GemFilter
+	preset  string // the preset the filter was picked as, until edited
+	presets map[string]domain.GemFilter
+	input   textinput.Model
+	field   filterField
+	err     string
+	shown   int
+	total   int
+	active  bool
+	width   int
+}
+
+// NewFilterBar creates an empty filter bar.
+func NewFilterBar() FilterBarModel {
+	ti := textinput.New()
+	ti.CharLimit = 32
+	ti.Width = 20
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(tui.ColorLavender)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(tui.ColorText)
+
+	return FilterBarModel{input: ti}
+}
+
+// SetPresets sets the named filters p cycles through.
+func (m *FilterBarModel) SetPresets(presets map[string]domain.GemFilter) {
+	m.presets = presets
+	if _, ok := presets[m.preset]; !ok {
+		m.preset = ""
+	}
+}
+
+// AddPreset adds a saved preset, which the filter now is.
+func (m *FilterBarModel) AddPreset(name string, f domain.GemFilter) {
+	presets := make(map[string]domain.GemFilter, len(m.presets)+1)
+	for n, p := range m.presets {
+		presets[n] = p
+	}
+	presets[name] = f
+	m.presets = presets
+	if f == m.filter {
+		m.preset = name
+	}
+}
+
+// Filter returns the filter and the name of the preset it was picked as,
+// empty if it has been edited since.
+func (m FilterBarModel) Filter() (domain.GemFilter, string) { return m.filter, m.preset }
+
+// SetFilter sets the filter, restoring the preset name only if the preset
+// still matches.
+func (m *FilterBarModel) SetFilter(f domain.GemFilter, preset string) {
+	m.filter = f
+	m.preset = ""
+	if p, ok := m.presets[preset]; ok && p == f {
+		m.preset = preset
+	}
+}
+
+// SetCounts sets how many of the tab's gems pass the filter.
+func (m *FilterBarModel) SetCounts(shown, total int) { m.shown, m.total = shown, total }
+
+func (m *FilterBarModel) SetWidth(w int) { m.width = w }
+
+// Open focuses the bar for editing.
+func (m *FilterBarModel) Open() {
+	m.active = true
+	m.err = ""
+}
+
+func (m FilterBarModel) Active() bool { return m.active }
+
+// Typing reports whether a value is being typed, when keys are text.
+func (m FilterBarModel) Typing() bool { return m.field != fieldNone }
+
+// Height is the number of lines the bar takes up.
+func (m FilterBarModel) Height() int {
+	if m.active || !m.filter.IsZero() {
+		return 1
+	}
+	return 0
+}
+
+func (m FilterBarModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m FilterBarModel) Update(msg tea.Msg) (FilterBarModel, tea.Cmd) {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		if m.field != fieldNone {
+			m.input, cmd = m.input.Update(msg)
+		}
+		return m, cmd
+	}
+	if m.field != fieldNone {
+		return m.updateInput(km)
+	}
+
+	m.err = ""
+	switch {
+	case key.Matches(km, tui.Keys.Back), key.Matches(km, tui.Keys.Select), key.Matches(km, tui.Keys.Filter):
+		m.active = false
+	case key.Matches(km, filterMinEV):
+		return m, m.prompt(fieldMinEV, "Min EV: ", formatNumber(m.filter.MinEV))
+	case key.Matches(km, filterMinListed):
+		return m, m.prompt(fieldMinListed, "Min listed: ", formatNumber(float64(m.filter.MinListed)))
+	case key.Matches(km, filterAllListed):
+		m.filter.AllListed = !m.filter.AllListed
+		m.preset = ""
+	case key.Matches(km, filterFavorites):
+		m.filter.Favorites = !m.filter.Favorites
+		m.preset = ""
+	case key.Matches(km, filterPreset):
+		m.nextPreset()
+	case key.Matches(km, filterSave):
+		if m.filter.IsZero() {
+			m.err = "Nothing to save"
+			return m, nil
+		}
+		return m, m.prompt(fieldPresetName, "Save as: ", m.preset)
+	case key.Matches(km, filterClear):
+		m.filter = domain.GemFilter{}
+		m.preset = ""
+	}
+	return m, nil
+}
+
+func (m *FilterBarModel) prompt(f filterField, prompt, value string) tea.Cmd {
+	m.field = f
+	m.input.Prompt = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+	return textinput.Blink
+}
+
+// updateInput handles keys while a value is being typed. An empty number
+// turns its rule off.
+func (m FilterBarModel) updateInput(msg tea.KeyMsg) (FilterBarModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.field = fieldNone
+		m.input.Blur()
+		return m, nil
+	case "enter":
+	default:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	value := strings.TrimSpace(m.input.Value())
+	switch m.field {
+	case fieldMinEV:
+		v, err := strconv.ParseFloat(strings.TrimSuffix(orZero(value), "c"), 64)
+		if err != nil || v < 0 {
+			m.err = fmt.Sprintf("Not a chaos value: %q", value)
+			return m, nil
+		}
+		m.filter.MinEV = v
+	case fieldMinListed:
+		n, err := strconv.Atoi(orZero(value))
+		if err != nil || n < 0 {
+			m.err = fmt.Sprintf("Not a count: %q", value)
+			return m, nil
+		}
+		m.filter.MinListed = n
+	case fieldPresetName:
+		if value == "" {
+			return m, nil
+		}
+		m.field = fieldNone
+		m.input.Blur()
+		f := m.filter
+		return m, func() tea.Msg { return tui.SaveFilterMsg{Name: value, Filter: f} }
+	}
+	m.err = ""
+	m.preset = ""
+	m.field = fieldNone
+	m.input.Blur()
+	return m, nil
+}
+
+// nextPreset applies the preset after the current one by name, and after
+// the last clears the filter.
+func (m *FilterBarModel) nextPreset() {
+	if len(m.presets) == 0 {
+		m.err = "No presets; press w to save one"
+		return
+	}
+	names := make([]string, 0, len(m.presets))
+	for name := range m.presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	i := sort.SearchStrings(names, m.preset)
+	if m.preset != "" {
+		i++
+	}
+	if i >= len(names) {
+		m.filter, m.preset = domain.GemFilter{}, ""
+		return
+	}
+	m.filter, m.preset = m.presets[names[i]], names[i]
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func formatNumber(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (m FilterBarModel) View() string {
+	if m.Height() == 0 {
+		return ""
+	}
+
+	left := " " + tui.StyleSubtle.Render("Filter")
+	if m.preset != "" {
+		left += tui.StyleSubtle.Render(tui.Separator) +
+			lipgloss.NewStyle().Bold(true).Foreground(tui.ColorLavender).Render(m.preset)
+	}
+	chips := m.filter.Chips()
+	if len(chips) == 0 {
+		left += " " + tui.StyleSubtle.Render("none")
+	}
+	for _, c := range chips {
+		left += " " + styleChip.Render(c)
+	}
+	if !m.filter.IsZero() {
+		left += tui.StyleSubtle.Render(fmt.Sprintf("%s%d of %d shown", tui.Separator, m.shown, m.total))
+	}
+
+	room := m.width - lipgloss.Width(left) - 2
+	var right string
+	if m.field != fieldNone {
+		right = m.input.View() + "  "
+	}
+	switch {
+	case m.err != "":
+		right += tui.StyleError.Render(m.err)
+	case m.field == fieldNone && m.active:
+		right = tui.StyleHelp.Render(filterHelp(room))
+	}
+
+	gap := max(m.width-lipgloss.Width(left)-lipgloss.Width(right)-1, 1)
+	return left + strings.Repeat(" ", gap) + right
+}
+
+// filterHelp lists the filter bar's keys that fit in room columns.
+func filterHelp(room int) string {
+	var parts []string
+	for _, b := range []key.Binding{
+		filterMinEV, filterMinListed, filterAllListed, filterFavorites,
+		filterPreset, filterSave, filterClear, tui.Keys.Back,
+	} {
+		part := b.Help().Key + " " + b.Help().Desc
+		if lipgloss.Width(strings.Join(append(parts, part), "  ")) > room {
+			break
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "  ")
+}
@@ -3,6 +3,8 @@ package components
 import (
 	"fmt"
 	"io"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -14,17 +16,79 @@ import (
 	"github.com/ovestokke/gemcheck-tui/internal/tui"
 )
 
+// Sort keys of the gem table's columns, as saved with the UI state.
+const (
+	SortGem       = "gem"
+	SortEV        = "ev"
+	SortBestOf    = "best_of"
+	SortBest      = "best"
+	SortWorst     = "worst"
+	SortVariants  = "variants"
+	SortMinListed = "min_listed"
+	SortNetProfit = "net_profit"
+	SortScore     = "score"
+)
+
+// columnForStrategy maps built-in ranking strategies to the column that
+// already shows their score, so they don't get a separate score column.
+var columnForStrategy = map[string]string{
+	domain.RankEV:        SortEV,
+	domain.RankNetProfit: SortNetProfit,
+	domain.RankBestOf:    SortBestOf,
+	domain.RankMaxPrice:  SortBest,
+}
+
+const (
+	columnGap     = 2
+	minNameWidth  = 16
+	selectionMark = 2 // the "┃ " border
+)
+
 // gemEntryItem adapts domain.GemEntry to list.Item.
 type gemEntryItem struct {
-	entry domain.GemEntry
+	entry   domain.GemEntry
+	delta   *domain.GemDelta
+	watched bool
 }
 
 func (i gemEntryItem) FilterValue() string { return i.entry.BaseName }
 
-// itemDelegate renders gem entries with left-border selection and price tiers.
-type itemDelegate struct{}
+// gemColumn is one column of the gem table.
+type gemColumn struct {
+	key   string
+	title string
+	width int
+
+	// drop orders columns for hiding when the table is too narrow, highest
+	// first; 0 is never hidden.
+	drop int
+
+	// value is the sort key, highest first by default; NaN rows have none
+	// and sort last either way. Columns without one sort by text or not at
+	// all.
+	value func(gemEntryItem) float64
+	cell  func(gemEntryItem) string // rendered; the gem column renders its own
+}
+
+func chaosCell(v float64) string {
+	return tui.PriceStyle(v).Render(domain.FormatChaos(v))
+}
+
+func countCell(n int) string {
+	return tui.StyleSubtle.Render(fmt.Sprint(n))
+}
+
+// padLeft right-aligns a rendered cell in width columns.
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-lipgloss.Width(s), 0)) + s
+}
+
+// itemDelegate renders gem entries as table rows with left-border selection.
+type itemDelegate struct {
+	columns []gemColumn
+}
 
-func (d itemDelegate) Height() int                             { return 2 }
+func (d itemDelegate) Height() int                             { return 1 }
 func (d itemDelegate) Spacing() int                            { return 0 }
 func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 
@@ -33,63 +97,56 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	if !ok {
 		return
 	}
-
-	e := item.entry
 	selected := index == m.Index()
-	width := m.Width()
-
-	gemColor := tui.ColorForGem(string(e.Color))
-	evStr := domain.FormatChaos(e.EV) + " EV"
-	priceStyle := tui.PriceStyle(e.EV)
 
-	var border, name, line2prefix string
+	border := "  "
 	if selected {
-		border = lipgloss.NewStyle().Foreground(gemColor).Render("\u2503 ")
-		name = lipgloss.NewStyle().Bold(true).Foreground(tui.ColorText).Render(e.BaseName)
-		line2prefix = lipgloss.NewStyle().Foreground(gemColor).Render("\u2503 ")
-	} else {
-		border = "  "
-		name = lipgloss.NewStyle().Foreground(tui.ColorSubtext0).Render(e.BaseName)
-		line2prefix = "  "
+		border = lipgloss.NewStyle().Foreground(tui.ColorForGem(string(item.entry.Color))).Render("┃ ")
 	}
 
-	// Line 1: border + name ... EV (right-aligned)
-	evRendered := priceStyle.Render(evStr)
-	nameWidth := lipgloss.Width(border) + lipgloss.Width(name)
-	evWidth := lipgloss.Width(evRendered)
-	gap := width - nameWidth - evWidth - 1
-	if gap < 1 {
-		gap = 1
-	}
-	line1 := border + name + strings.Repeat(" ", gap) + evRendered
-
-	// Line 2: border + variant count + best price
-	var bestPrice float64
-	for _, v := range e.Variants {
-		if v.SellPrice > bestPrice {
-			bestPrice = v.SellPrice
+	var b strings.Builder
+	b.WriteString(border)
+	for i, c := range d.columns {
+		if i > 0 {
+			b.WriteString(strings.Repeat(" ", columnGap))
+		}
+		if c.key == SortGem {
+			style := lipgloss.NewStyle().Foreground(tui.ColorSubtext0)
+			if selected {
+				style = style.Bold(true).Foreground(tui.ColorText)
+			}
+			name, width := item.entry.BaseName, c.width
+			if item.watched {
+				b.WriteString(tui.StyleWatched.Render(tui.WatchedMark))
+				width -= lipgloss.Width(tui.WatchedMark)
+			}
+			b.WriteString(style.Render(fmt.Sprintf("%-*s", width, truncate(name, width))))
+			continue
 		}
+		b.WriteString(padLeft(c.cell(item), c.width))
 	}
-	detailText := fmt.Sprintf("  %d variants%sbest: %s",
-		e.VariantCount, tui.Separator, domain.FormatChaos(bestPrice))
-	line2 := line2prefix + tui.StyleSubtle.Render(detailText)
-
-	fmt.Fprintf(w, "%s\n%s", line1, line2)
+	fmt.Fprint(w, b.String())
 }
 
-// GemTableModel is a scrollable gem list for a single color tab.
+// GemTableModel is a scrollable, sortable gem table for a single color tab.
 type GemTableModel struct {
-	list   list.Model
-	color  domain.GemColor
-	width  int
-	height int
+	list     list.Model
+	entries  []domain.GemEntry
+	color    domain.GemColor
+	strategy domain.RankStrategy
+	deltas   map[string]domain.GemDelta
+	watched  map[string]bool
+	columns  []gemColumn // all columns for the strategy and deltas
+	visible  []gemColumn // those that fit the width
+	sortKey  string
+	sortAsc  bool
+	width    int
+	height   int
 }
 
-// NewGemTable creates an empty gem table.
+// NewGemTable creates an empty gem table sorted by EV.
 func NewGemTable(width, height int) GemTableModel {
-	delegate := itemDelegate{}
-
-	l := list.New(nil, delegate, width, height)
+	l := list.New(nil, itemDelegate{}, width, height-1)
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
@@ -98,19 +155,280 @@ func NewGemTable(width, height int) GemTableModel {
 	l.KeyMap.CursorUp = key.NewBinding(key.WithKeys("up", "k"))
 	l.KeyMap.CursorDown = key.NewBinding(key.WithKeys("down", "j"))
 
-	return GemTableModel{list: l, width: width, height: height}
+	m := GemTableModel{list: l, strategy: domain.RankStrategies[0], sortKey: SortEV, width: width, height: height}
+	m.layout()
+	return m
+}
+
+// SetStrategy sets the ranking strategy. Strategies without a column of
+// their own get a score column.
+func (m *GemTableModel) SetStrategy(s domain.RankStrategy) {
+	m.strategy = s
+	m.layout()
+}
+
+// SortByStrategy sorts by the current strategy's column, highest first.
+func (m *GemTableModel) SortByStrategy() {
+	m.sortKey, m.sortAsc = SortScore, false
+	if k, ok := columnForStrategy[m.strategy.Name]; ok {
+		m.sortKey = k
+	}
+	m.resort()
+}
+
+// SetDeltas sets the changes since the last refresh, by base name, shown as
+// ▲/▼ markers in a changes column. Pass nil to clear them.
+func (m *GemTableModel) SetDeltas(deltas map[string]domain.GemDelta) {
+	m.deltas = deltas
+	m.layout()
+}
+
+// SetWatched sets the watched base gems, by name, marked with a star. It
+// applies from the next SetEntries.
+func (m *GemTableModel) SetWatched(watched map[string]bool) {
+	m.watched = watched
 }
 
-// SetEntries populates the table with gem entries for a given color.
+// SetEntries populates the table with gem entries for a given color, in the
+// current sort order. Ties keep the order of entries.
 func (m *GemTableModel) SetEntries(entries []domain.GemEntry, color domain.GemColor) {
+	m.entries = entries
 	m.color = color
-	items := make([]list.Item, 0, len(entries))
+
+	sortCol, ok := m.column(m.sortKey)
+	if !ok {
+		// The score column went away with its strategy
+		m.sortKey, m.sortAsc = SortEV, false
+		sortCol, _ = m.column(SortEV)
+	}
+
+	items := make([]gemEntryItem, 0, len(entries))
 	for _, e := range entries {
 		if e.Color == color {
-			items = append(items, gemEntryItem{entry: e})
+			item := gemEntryItem{entry: e, watched: m.watched[e.BaseName]}
+			if d, ok := m.deltas[e.BaseName]; ok {
+				item.delta = &d
+			}
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if sortCol.value == nil {
+			a, b := strings.ToLower(items[i].entry.BaseName), strings.ToLower(items[j].entry.BaseName)
+			if m.sortAsc {
+				return a < b
+			}
+			return a > b
+		}
+		a, b := sortCol.value(items[i]), sortCol.value(items[j])
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a)
+		}
+		if m.sortAsc {
+			return a < b
+		}
+		return a > b
+	})
+
+	listItems := make([]list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = item
+	}
+	m.list.SetItems(listItems)
+	// Keep the cursor on a row when switching to a shorter tab
+	if m.list.Index() >= len(listItems) {
+		m.list.Select(max(len(listItems)-1, 0))
+	}
+}
+
+// Entries returns the active color's entries in table order.
+func (m GemTableModel) Entries() []domain.GemEntry {
+	items := m.list.Items()
+	entries := make([]domain.GemEntry, len(items))
+	for i, it := range items {
+		entries[i] = it.(gemEntryItem).entry
+	}
+	return entries
+}
+
+// Sort returns the sort column's key and whether it sorts ascending.
+func (m GemTableModel) Sort() (string, bool) { return m.sortKey, m.sortAsc }
+
+// SetSort sorts by the column with key. Unknown keys sort by EV.
+func (m *GemTableModel) SetSort(key string, asc bool) {
+	m.sortKey, m.sortAsc = key, asc
+	m.resort()
+}
+
+// NextSort sorts by the next visible column, numbers highest first and
+// names A to Z.
+func (m *GemTableModel) NextSort() {
+	i := 0
+	for j, c := range m.visible {
+		if c.key == m.sortKey {
+			i = j + 1
+			break
+		}
+	}
+	for ; ; i++ {
+		c := m.visible[i%len(m.visible)]
+		if c.value != nil || c.key == SortGem {
+			m.sortKey, m.sortAsc = c.key, c.key == SortGem
+			break
+		}
+	}
+	m.resort()
+}
+
+// ReverseSort flips the sort direction.
+func (m *GemTableModel) ReverseSort() {
+	m.sortAsc = !m.sortAsc
+	m.resort()
+}
+
+// resort reorders the rows, keeping the same gem selected.
+func (m *GemTableModel) resort() {
+	selected := m.SelectedEntry()
+	m.SetEntries(m.entries, m.color)
+	if selected != nil {
+		m.Select(selected.BaseName)
+	}
+}
+
+func (m GemTableModel) column(key string) (gemColumn, bool) {
+	for _, c := range m.columns {
+		if c.key == key {
+			return c, true
+		}
+	}
+	return gemColumn{}, false
+}
+
+// layout builds the columns for the strategy and deltas and fits them to
+// the width.
+func (m *GemTableModel) layout() {
+	bestOf := fmt.Sprintf("Best-of-%d", domain.FontDraws)
+	m.columns = []gemColumn{
+		{key: SortGem, title: "Gem"},
+	}
+	if len(m.deltas) > 0 {
+		m.columns = append(m.columns, gemColumn{key: "change", title: "Change", width: 9, drop: 4,
+			cell: func(i gemEntryItem) string {
+				d := i.delta
+				if d == nil {
+					return ""
+				}
+				if d.EVChange() == 0 && d.RankChange() != 0 {
+					return renderRankChange(d.RankChange())
+				}
+				return renderDelta(d.EVChange())
+			}})
+	}
+	m.columns = append(m.columns,
+		gemColumn{key: SortEV, title: "EV",
+			value: func(i gemEntryItem) float64 { return i.entry.EV },
+			cell:  func(i gemEntryItem) string { return chaosCell(i.entry.EV) }},
+		gemColumn{key: SortBestOf, title: bestOf, drop: 5,
+			value: func(i gemEntryItem) float64 { return i.entry.BestOfEV(domain.FontDraws) },
+			cell:  func(i gemEntryItem) string { return chaosCell(i.entry.BestOfEV(domain.FontDraws)) }},
+		gemColumn{key: SortBest, title: "Best", drop: 3,
+			value: func(i gemEntryItem) float64 { return i.entry.MaxPrice() },
+			cell:  func(i gemEntryItem) string { return chaosCell(i.entry.MaxPrice()) }},
+		gemColumn{key: SortWorst, title: "Worst", drop: 7,
+			value: func(i gemEntryItem) float64 { return i.entry.MinPrice() },
+			cell:  func(i gemEntryItem) string { return chaosCell(i.entry.MinPrice()) }},
+		gemColumn{key: SortVariants, title: "Variants", drop: 6,
+			value: func(i gemEntryItem) float64 { return float64(i.entry.VariantCount) },
+			cell:  func(i gemEntryItem) string { return countCell(i.entry.VariantCount) }},
+		gemColumn{key: SortMinListed, title: "Min listed", drop: 8,
+			value: func(i gemEntryItem) float64 { return float64(i.entry.MinCount()) },
+			cell:  func(i gemEntryItem) string { return countCell(i.entry.MinCount()) }},
+		gemColumn{key: SortNetProfit, title: "Net profit", drop: 2,
+			value: func(i gemEntryItem) float64 {
+				if !i.entry.BasePriced {
+					return math.NaN()
+				}
+				return i.entry.NetProfit()
+			},
+			cell: func(i gemEntryItem) string {
+				// Without a base price there is nothing to subtract
+				if !i.entry.BasePriced {
+					return tui.StyleSubtle.Render("—")
+				}
+				switch v := i.entry.NetProfit(); {
+				case v > 0:
+					return tui.StyleProb.Render(domain.FormatChaosSigned(v))
+				case v < 0:
+					return tui.StyleError.Render(domain.FormatChaosSigned(v))
+				}
+				return tui.StyleSubtle.Render(domain.FormatChaos(0))
+			}},
+	)
+	if _, ok := columnForStrategy[m.strategy.Name]; !ok {
+		s := m.strategy
+		m.columns = append(m.columns, gemColumn{key: SortScore, title: truncate(s.Name, 14), drop: 1,
+			value: func(i gemEntryItem) float64 { return s.Score(i.entry) },
+			cell: func(i gemEntryItem) string {
+				return lipgloss.NewStyle().Foreground(tui.ColorLavender).Render(s.FormatScore(s.Score(i.entry)))
+			}})
+	}
+
+	// Room for the title and a sort arrow
+	for i := range m.columns {
+		m.columns[i].width = max(m.columns[i].width, 8, lipgloss.Width(m.columns[i].title)+2)
+	}
+	m.fit()
+}
+
+// fit picks the columns that fit the width, hiding the highest drop first,
+// and gives the rest of the width to the gem name.
+func (m *GemTableModel) fit() {
+	visible := append([]gemColumn(nil), m.columns...)
+	used := func() int {
+		w := selectionMark + minNameWidth + 1
+		for _, c := range visible[1:] {
+			w += columnGap + c.width
+		}
+		return w
+	}
+	for used() > m.width {
+		worst := -1
+		for i, c := range visible {
+			if c.drop > 0 && (worst < 0 || c.drop > visible[worst].drop) {
+				worst = i
+			}
 		}
+		if worst < 0 {
+			break
+		}
+		visible = append(visible[:worst], visible[worst+1:]...)
 	}
-	m.list.SetItems(items)
+	visible[0].width = minNameWidth + max(m.width-used(), 0)
+	m.visible = visible
+	m.list.SetDelegate(itemDelegate{columns: visible})
+}
+
+// header renders the column titles, marking the sort column with its
+// direction.
+func (m GemTableModel) header() string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", selectionMark))
+	for i, c := range m.visible {
+		if i > 0 {
+			b.WriteString(strings.Repeat(" ", columnGap))
+		}
+		title, style := c.title, tui.StyleSubtle
+		if c.key == m.sortKey {
+			title += map[bool]string{true: " ▲", false: " ▼"}[m.sortAsc]
+			style = lipgloss.NewStyle().Bold(true).Foreground(tui.ColorLavender)
+		}
+		if c.key == SortGem {
+			b.WriteString(style.Render(fmt.Sprintf("%-*s", c.width, title)))
+		} else {
+			b.WriteString(style.Render(fmt.Sprintf("%*s", c.width, title)))
+		}
+	}
+	return b.String()
 }
 
 // SelectedEntry returns the currently highlighted gem entry, if any.
@@ -122,11 +440,23 @@ func (m GemTableModel) SelectedEntry() *domain.GemEntry {
 	return &item.entry
 }
 
-// SetSize updates the table dimensions.
+// Select highlights the row for baseName, reporting whether it is listed.
+func (m *GemTableModel) Select(baseName string) bool {
+	for i, item := range m.list.Items() {
+		if item.(gemEntryItem).entry.BaseName == baseName {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// SetSize updates the table dimensions, including the header row.
 func (m *GemTableModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
-	m.list.SetSize(width, height)
+	m.list.SetSize(width, height-1)
+	m.fit()
 }
 
 func (m GemTableModel) Init() tea.Cmd {
@@ -140,5 +470,5 @@ func (m GemTableModel) Update(msg tea.Msg) (GemTableModel, tea.Cmd) {
 }
 
 func (m GemTableModel) View() string {
-	return m.list.View()
+	return m.header() + "\n" + m.list.View()
 }
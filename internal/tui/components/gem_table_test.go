@@ -0,0 +1,152 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func tableEntries() []domain.GemEntry {
+	return []domain.GemEntry{
+		{BaseName: "cleave", Color: domain.Red, EV: 5, VariantCount: 2, BaseCost: 1, BasePriced: true},
+		{BaseName: "Boneshatter", Color: domain.Red, EV: 20, VariantCount: 3, BaseCost: 30, BasePriced: true},
+		{BaseName: "Arc", Color: domain.Blue, EV: 50},
+		{BaseName: "Sunder", Color: domain.Red, EV: 5, VariantCount: 1},
+		{BaseName: "Anger", Color: domain.Red, EV: 10, VariantCount: 1, BaseCost: 2, BasePriced: true},
+	}
+}
+
+func tableNames(m GemTableModel) []string {
+	var names []string
+	for _, e := range m.Entries() {
+		names = append(names, e.BaseName)
+	}
+	return names
+}
+
+func visibleKeys(m GemTableModel) []string {
+	keys := make([]string, len(m.visible))
+	for i, c := range m.visible {
+		keys[i] = c.key
+	}
+	return keys
+}
+
+func TestGemTableSetEntriesOrder(t *testing.T) {
+	for _, tt := range []struct {
+		key  string
+		asc  bool
+		want []string
+	}{
+		// EV ties keep the order of entries
+		{SortEV, false, []string{"Boneshatter", "Anger", "cleave", "Sunder"}},
+		{SortEV, true, []string{"cleave", "Sunder", "Anger", "Boneshatter"}},
+		// Names sort case-insensitively
+		{SortGem, true, []string{"Anger", "Boneshatter", "cleave", "Sunder"}},
+		{SortGem, false, []string{"Sunder", "cleave", "Boneshatter", "Anger"}},
+		{SortVariants, false, []string{"Boneshatter", "cleave", "Sunder", "Anger"}},
+		// Gems without a base price go last either way
+		{SortNetProfit, false, []string{"Anger", "cleave", "Boneshatter", "Sunder"}},
+		{SortNetProfit, true, []string{"Boneshatter", "cleave", "Anger", "Sunder"}},
+		// Unknown keys sort by EV
+		{"nope", false, []string{"Boneshatter", "Anger", "cleave", "Sunder"}},
+	} {
+		m := NewGemTable(200, 20)
+		m.SetSort(tt.key, tt.asc)
+		m.SetEntries(tableEntries(), domain.Red)
+		if got := tableNames(m); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %s asc=%v: got %v, want %v", tt.key, tt.asc, got, tt.want)
+		}
+	}
+}
+
+func TestGemTableNextSort(t *testing.T) {
+	m := NewGemTable(200, 20)
+	m.SetEntries(tableEntries(), domain.Red)
+
+	want := []string{SortBestOf, SortBest, SortWorst, SortVariants, SortMinListed, SortNetProfit, SortGem, SortEV}
+	for _, key := range want {
+		m.NextSort()
+		got, asc := m.Sort()
+		if got != key || asc != (key == SortGem) {
+			t.Fatalf("NextSort = %s asc=%v, want %s asc=%v", got, asc, key, key == SortGem)
+		}
+	}
+
+	// Hidden columns are skipped
+	m.SetSize(45, 20)
+	m.SetSort(SortEV, false)
+	m.NextSort()
+	if got, _ := m.Sort(); got != SortNetProfit {
+		t.Errorf("NextSort at width 45 = %s, want %s", got, SortNetProfit)
+	}
+}
+
+func TestGemTableReverseSort(t *testing.T) {
+	m := NewGemTable(200, 20)
+	m.SetEntries(tableEntries(), domain.Red)
+	m.Select("Anger")
+
+	m.ReverseSort()
+	if key, asc := m.Sort(); key != SortEV || !asc {
+		t.Errorf("Sort = %s asc=%v, want %s asc=true", key, asc, SortEV)
+	}
+	if got, want := tableNames(m), []string{"cleave", "Sunder", "Anger", "Boneshatter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if e := m.SelectedEntry(); e == nil || e.BaseName != "Anger" {
+		t.Errorf("selection moved to %v", e)
+	}
+}
+
+func TestGemTableFit(t *testing.T) {
+	m := NewGemTable(200, 20)
+	all := visibleKeys(m)
+	if len(all) != len(m.columns) {
+		t.Fatalf("wide table hides columns: %v", all)
+	}
+
+	// Shrinking hides columns from the highest drop down, never EV
+	var dropped []string
+	prev := all
+	for w := 200; w >= 10; w-- {
+		m.SetSize(w, 20)
+		keys := visibleKeys(m)
+		for _, k := range prev {
+			if !contains(keys, k) {
+				dropped = append(dropped, k)
+			}
+		}
+		prev = keys
+	}
+	want := []string{SortMinListed, SortWorst, SortVariants, SortBestOf, SortBest, SortNetProfit}
+	if !reflect.DeepEqual(dropped, want) {
+		t.Errorf("dropped %v, want %v", dropped, want)
+	}
+	if !reflect.DeepEqual(prev, []string{SortGem, SortEV}) {
+		t.Errorf("narrowest table shows %v", prev)
+	}
+
+	// A strategy without a column of its own gets a score column, kept
+	// after all but EV
+	m = NewGemTable(200, 20)
+	for _, s := range domain.RankStrategies {
+		if s.Name == domain.RankRiskAdjusted {
+			m.SetStrategy(s)
+		}
+	}
+	m.SetSize(50, 20)
+	if got, want := visibleKeys(m), []string{SortGem, SortEV, SortScore}; !reflect.DeepEqual(got, want) {
+		t.Errorf("width 50 shows %v, want %v", got, want)
+	}
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -11,17 +11,19 @@ import (
 )
 
 type GemTabsModel struct {
-	ActiveTab  int
-	Tabs       []string
-	Colors     []domain.GemColor
-	PoolStats  *domain.ColorStats
-	TotalGems  int
+	ActiveTab   int
+	Tabs        []string
+	Colors      []domain.GemColor
+	PoolStats   *domain.ColorStats
+	TotalGems   int
+	Strategy    string
+	StrategyErr error
 }
 
 func NewGemTabs() GemTabsModel {
 	return GemTabsModel{
 		ActiveTab: 0,
-		Tabs:      []string{"Red", "Green", "Blue"},
+		Tabs:      []string{"Red", "Green", "Blue", "Watchlist"},
 		Colors:    []domain.GemColor{domain.Red, domain.Green, domain.Blue},
 	}
 }
@@ -36,15 +38,32 @@ func (m *GemTabsModel) NextTab() {
 	m.ActiveTab = (m.ActiveTab + 1) % len(m.Tabs)
 }
 
+// ActiveColor returns the active tab's color, or "" on the Watchlist tab.
 func (m GemTabsModel) ActiveColor() domain.GemColor {
+	if m.Watchlist() {
+		return ""
+	}
 	return m.Colors[m.ActiveTab]
 }
 
+// Watchlist reports whether the Watchlist tab, after the color tabs, is
+// active.
+func (m GemTabsModel) Watchlist() bool {
+	return m.ActiveTab == len(m.Colors)
+}
+
 func (m *GemTabsModel) SetPoolStats(stats *domain.ColorStats, totalGems int) {
 	m.PoolStats = stats
 	m.TotalGems = totalGems
 }
 
+// SetStrategy sets the ranking name shown next to the pool stats, with the
+// parse error of a user-defined score if it has one.
+func (m *GemTabsModel) SetStrategy(name string, err error) {
+	m.Strategy = name
+	m.StrategyErr = err
+}
+
 func (m GemTabsModel) View(width int) string {
 	// Logo pill
 	logo := tui.StyleLogo.Render(" \u25c6 GemCheck ")
@@ -52,7 +71,10 @@ func (m GemTabsModel) View(width int) string {
 	// Tabs
 	var tabs []string
 	for i, t := range m.Tabs {
-		c := tui.ColorForGem(string(m.Colors[i]))
+		c := tui.ColorGold
+		if i < len(m.Colors) {
+			c = tui.ColorForGem(string(m.Colors[i]))
+		}
 		if i == m.ActiveTab {
 			style := tui.StyleTabActive.
 				Foreground(c).
@@ -71,6 +93,22 @@ func (m GemTabsModel) View(width int) string {
 		statsStr = tui.StyleSubtle.Render(
 			fmt.Sprintf("%d gems%sPool EV: %s",
 				m.PoolStats.PoolSize, tui.Separator, domain.FormatChaos(m.PoolStats.PoolEV)))
+		if m.PoolStats.Excluded > 0 {
+			statsStr = tui.StyleSubtle.Render(fmt.Sprintf("%d excluded", m.PoolStats.Excluded)) +
+				tui.Separator + statsStr
+		}
+	}
+	if m.Strategy != "" {
+		rank := tui.StyleSubtle.Render("Rank: ") +
+			lipgloss.NewStyle().Foreground(tui.ColorLavender).Render(m.Strategy)
+		if m.StrategyErr != nil {
+			rank += " " + tui.StyleError.Render("\u26a0 "+m.StrategyErr.Error())
+		}
+		if statsStr != "" {
+			statsStr = rank + tui.Separator + statsStr
+		} else {
+			statsStr = rank
+		}
 	}
 
 	leftSection := lipgloss.JoinHorizontal(lipgloss.Bottom, logo, "  ", tabRow)
@@ -79,7 +117,9 @@ func (m GemTabsModel) View(width int) string {
 		statsWidth := lipgloss.Width(statsStr)
 		gap := width - leftWidth - statsWidth - 2
 		if gap < 1 {
+			// Truncate rather than wrap the header
 			gap = 1
+			statsStr = lipgloss.NewStyle().MaxWidth(max(0, width-leftWidth-3)).Render(statsStr)
 		}
 		leftSection = leftSection + strings.Repeat(" ", gap) + statsStr
 	}
@@ -1,7 +1,14 @@
 package components
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -9,27 +16,178 @@ import (
 	"github.com/ovestokke/gemcheck-tui/internal/tui"
 )
 
+// toggleSSF shows or hides SSF leagues, which poe.ninja has no prices for.
+var toggleSSF = key.NewBinding(
+	key.WithKeys("s"),
+	key.WithHelp("s", "toggle SSF"),
+)
+
+// enterID opens free-text entry of a league ID, for leagues that aren't
+// listed such as private ones.
+var enterID = key.NewBinding(
+	key.WithKeys("e"),
+	key.WithHelp("e", "enter league ID"),
+)
+
 type leagueItem struct {
 	league domain.League
+	note   string
+	day    int
 }
 
-func (i leagueItem) Title() string       { return i.league.Text }
-func (i leagueItem) Description() string { return "" }
-func (i leagueItem) FilterValue() string { return i.league.Text }
+func (i leagueItem) Title() string {
+	if i.note != "" {
+		return i.league.Text + " · " + i.note
+	}
+	return i.league.Text
+}
+
+func (i leagueItem) Description() string {
+	parts := []string{i.league.Kind()}
+	if i.day > 0 {
+		parts = append(parts, fmt.Sprintf("day %d", i.day))
+	}
+	if r := i.league.Realm; r != "" && r != "pc" {
+		parts = append(parts, r)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (i leagueItem) FilterValue() string { return i.league.Text + " " + i.league.Kind() }
 
 type LeagueSelectModel struct {
 	list     list.Model
 	Selected *domain.League
+	leagues  []domain.League
+	current  string
+	loaded   map[string]bool
+	showSSF  bool
+	title    string
+	input    textinput.Model
+	entering bool
+	err      error
+	popup    bool
+	compare  bool
+	active   bool
 	width    int
 	height   int
 }
 
+// NewLeagueSelect creates the full-screen league list shown at start-up,
+// grouped by kind with SSF leagues hidden until toggled.
 func NewLeagueSelect(leagues []domain.League, width, height int) LeagueSelectModel {
-	items := make([]list.Item, len(leagues))
-	for i, l := range leagues {
-		items[i] = leagueItem{league: l}
+	l := list.New(nil, leagueDelegate(), width, height-4)
+	l.Styles.Title = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(tui.ColorYellow).
+		Padding(0, 1)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(true)
+	l.DisableQuitKeybindings()
+	l.AdditionalShortHelpKeys = func() []key.Binding { return []key.Binding{toggleSSF, enterID} }
+	l.AdditionalFullHelpKeys = l.AdditionalShortHelpKeys
+
+	ti := textinput.New()
+	ti.Prompt = "League ID: "
+	ti.Placeholder = "e.g. My League (PL12345)"
+	ti.CharLimit = 64
+	ti.Width = 40
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(tui.ColorLavender)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(tui.ColorText)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(tui.ColorOverlay0)
+
+	m := LeagueSelectModel{list: l, leagues: leagues, title: "Select League", input: ti, width: width, height: height}
+	m.setItems("")
+	return m
+}
+
+// NewLeaguePicker creates the league list as a popup over the main screen,
+// with the cursor on the current league and the current and loaded leagues
+// marked.
+func NewLeaguePicker(leagues []domain.League, current string, loaded map[string]bool, width, height int) LeagueSelectModel {
+	m := NewLeagueSelect(leagues, width, height)
+	m.popup = true
+	m.active = true
+	m.current = current
+	m.loaded = loaded
+	m.title = "Switch League"
+	m.setItems(current)
+	m.resize()
+	return m
+}
+
+// NewComparePicker is NewLeaguePicker for choosing a league to compare the
+// current one with.
+func NewComparePicker(leagues []domain.League, current string, loaded map[string]bool, width, height int) LeagueSelectModel {
+	m := NewLeaguePicker(leagues, current, loaded, width, height)
+	m.compare = true
+	m.title = "Compare With"
+	m.setItems(current)
+	return m
+}
+
+// setItems lists the leagues grouped by kind, with the cursor on the league
+// with ID selected if it is listed.
+func (m *LeagueSelectModel) setItems(selected string) {
+	now := time.Now()
+	var items []list.Item
+	hidden := 0
+	for _, kind := range domain.LeagueKinds {
+		for _, l := range m.leagues {
+			if l.Kind() != kind {
+				continue
+			}
+			if l.SSF && !m.showSSF && l.ID != m.current {
+				hidden++
+				continue
+			}
+			item := leagueItem{league: l, day: l.Day(now)}
+			switch {
+			case l.ID == m.current:
+				item.note = "current"
+			case m.loaded[l.ID]:
+				item.note = "loaded"
+			}
+			items = append(items, item)
+		}
 	}
+	m.list.SetItems(items)
+
+	m.list.Title = m.title
+	if hidden > 0 {
+		m.list.Title += fmt.Sprintf(" · %d SSF hidden", hidden)
+	}
+
+	if i := slices.IndexFunc(items, func(it list.Item) bool {
+		return it.(leagueItem).league.ID == selected
+	}); i >= 0 {
+		m.list.Select(i)
+	}
+}
+
+// SetError notes that the league list couldn't be fetched, leaving entry
+// by ID as the way in.
+func (m *LeagueSelectModel) SetError(err error) {
+	m.err = err
+}
 
+// Active reports whether the popup is open. The full-screen list shown at
+// start-up is never active.
+func (m LeagueSelectModel) Active() bool { return m.active }
+
+// Hide closes the popup.
+func (m *LeagueSelectModel) Hide() { m.active = false }
+
+// resize fits the list to the screen, or to the popup box.
+func (m *LeagueSelectModel) resize() {
+	if !m.popup {
+		m.list.SetSize(m.width, m.height-4)
+		return
+	}
+	m.list.SetSize(min(60, m.width-4)-6, max(min(len(m.list.Items())*3+6, m.height-8), 8))
+}
+
+func leagueDelegate() list.DefaultDelegate {
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
 		Foreground(tui.ColorYellow).
@@ -37,18 +195,7 @@ func NewLeagueSelect(leagues []domain.League, width, height int) LeagueSelectMod
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
 		Foreground(tui.ColorSubtext0).
 		BorderLeftForeground(tui.ColorYellow)
-
-	l := list.New(items, delegate, width, height-4)
-	l.Title = "Select League"
-	l.Styles.Title = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(tui.ColorYellow).
-		Padding(0, 1)
-	l.SetShowStatusBar(false)
-	l.SetShowHelp(true)
-	l.DisableQuitKeybindings()
-
-	return LeagueSelectModel{list: l, width: width, height: height}
+	return delegate
 }
 
 func (m LeagueSelectModel) Init() tea.Cmd {
@@ -60,23 +207,102 @@ func (m LeagueSelectModel) Update(msg tea.Msg) (LeagueSelectModel, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width, msg.Height-4)
+		m.resize()
 	case tea.KeyMsg:
+		if m.entering {
+			return m.updateEntry(msg)
+		}
+		if key.Matches(msg, enterID) && !m.Filtering() {
+			m.entering = true
+			m.input.SetValue("")
+			m.input.Focus()
+			return m, textinput.Blink
+		}
+		if m.popup && msg.String() == "esc" && !m.Filtering() && !m.list.IsFiltered() {
+			m.Hide()
+			return m, nil
+		}
+		if key.Matches(msg, toggleSSF) && !m.Filtering() {
+			m.showSSF = !m.showSSF
+			var selected string
+			if item, ok := m.list.SelectedItem().(leagueItem); ok {
+				selected = item.league.ID
+			}
+			m.setItems(selected)
+			m.resize()
+			return m, nil
+		}
 		if msg.String() == "enter" {
 			if item, ok := m.list.SelectedItem().(leagueItem); ok {
-				m.Selected = &item.league
-				return m, func() tea.Msg {
-					return tui.LeagueSelectedMsg{League: item.league}
-				}
+				return m, m.choose(item.league)
 			}
 		}
 	}
 
 	var cmd tea.Cmd
-	m.list, cmd = m.list.Update(msg)
+	if m.entering {
+		m.input, cmd = m.input.Update(msg)
+	} else {
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+// updateEntry handles keys while a league ID is being typed.
+func (m LeagueSelectModel) updateEntry(msg tea.KeyMsg) (LeagueSelectModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.entering = false
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		id := strings.TrimSpace(m.input.Value())
+		if id == "" {
+			return m, nil
+		}
+		m.entering = false
+		m.input.Blur()
+		for _, l := range m.leagues {
+			if strings.EqualFold(l.ID, id) {
+				return m, m.choose(l)
+			}
+		}
+		return m, m.choose(domain.League{ID: id, Text: id})
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
 	return m, cmd
 }
 
+func (m *LeagueSelectModel) choose(l domain.League) tea.Cmd {
+	m.Selected = &l
+	m.active = false
+	compare := m.compare
+	return func() tea.Msg {
+		return tui.LeagueSelectedMsg{League: l, Compare: compare}
+	}
+}
+
+// Filtering reports whether the user is typing a filter or a league ID, when
+// keys are text and esc doesn't leave the list.
+func (m LeagueSelectModel) Filtering() bool {
+	return m.entering || m.list.FilterState() == list.Filtering
+}
+
 func (m LeagueSelectModel) View() string {
-	return m.list.View()
+	view := m.list.View()
+	if m.err != nil {
+		// Only the first line fits; the fallbacks' errors follow it
+		msg, _, _ := strings.Cut(m.err.Error(), "\n")
+		view += "\n" + tui.StyleError.Render(msg) +
+			"\n" + tui.StyleHelp.Render("Press e to enter a league ID")
+	}
+	if m.entering {
+		view += "\n" + m.input.View()
+	}
+	if !m.popup {
+		return view
+	}
+	popup := tui.StyleDetailPopup.Width(min(60, m.width-4)).Render(view)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
 }
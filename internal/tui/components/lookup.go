@@ -0,0 +1,136 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// LookupModel shows where a transfigured gem comes from and the chances of
+// rolling it from its base gem or its color pool.
+type LookupModel struct {
+	lookup  domain.VariantLookup
+	active  bool
+	watched map[string]bool
+	width   int
+	height  int
+}
+
+// NewLookup creates a lookup popup.
+func NewLookup() LookupModel {
+	return LookupModel{}
+}
+
+func (m *LookupModel) SetSize(w, h int) { m.width = w; m.height = h }
+func (m LookupModel) Active() bool      { return m.active }
+
+// Show displays the popup for a lookup.
+func (m *LookupModel) Show(l domain.VariantLookup) {
+	m.lookup = l
+	m.active = true
+}
+
+// Hide closes the popup.
+func (m *LookupModel) Hide() { m.active = false }
+
+// BaseName is the shown gem's base gem.
+func (m LookupModel) BaseName() string { return m.lookup.BaseName }
+
+// Variant is the shown transfigured gem.
+func (m LookupModel) Variant() domain.GemVariantResult { return m.lookup.Variant }
+
+// SetWatched sets the watched gems, by name, marked with a star.
+func (m *LookupModel) SetWatched(watched map[string]bool) {
+	m.watched = watched
+}
+
+func (m LookupModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m LookupModel) Update(msg tea.Msg) (LookupModel, tea.Cmd) {
+	if km, ok := msg.(tea.KeyMsg); ok && m.active {
+		switch km.String() {
+		case "esc":
+			m.Hide()
+		}
+	}
+	return m, nil
+}
+
+func (m LookupModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	l := m.lookup
+	v := l.Variant
+	popupWidth := min(60, m.width-4)
+	innerWidth := popupWidth - 6
+
+	var b strings.Builder
+
+	gemColor := tui.ColorForGem(string(l.Color))
+	title := lipgloss.NewStyle().Bold(true).Foreground(gemColor).Render(v.Name)
+	if m.watched[v.Name] {
+		title = tui.StyleWatched.Render(tui.WatchedMark) + title
+	}
+	b.WriteString(title + "\n")
+	b.WriteString(tui.StyleHeaderDivider.Render(strings.Repeat("─", innerWidth)) + "\n")
+
+	price := tui.StyleSubtle.Render("unlisted")
+	if v.Listed {
+		price = tui.PriceStyle(v.SellPrice).Render(domain.FormatChaos(v.SellPrice)) +
+			tui.StyleSubtle.Render(fmt.Sprintf(" (%d listed)", v.Count))
+	}
+	b.WriteString(fmt.Sprintf("%s pool%sfrom %s%s%s\n\n",
+		l.Color.Label(), tui.Separator,
+		lipgloss.NewStyle().Bold(true).Foreground(tui.ColorText).Render(l.BaseName),
+		tui.Separator, price))
+
+	if v.Excluded {
+		b.WriteString(tui.StyleError.Render("Excluded from font rolls") + "\n\n")
+	} else {
+		b.WriteString(m.chances())
+	}
+	b.WriteString(tui.StyleHelp.Render("esc close  enter base gem  " + tui.Keys.Watch.Help().Key + " watch"))
+
+	popup := tui.StyleDetailPopup.Width(popupWidth).Render(b.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// chances renders the chance per attempt of each way of rolling for the
+// gem, and the attempts to see it with each of domain.LookupTargets.
+func (m LookupModel) chances() string {
+	l := m.lookup
+	var b strings.Builder
+	header := fmt.Sprintf("%-22s %8s", "", "Per try")
+	for _, t := range domain.LookupTargets {
+		header += fmt.Sprintf(" %6s", fmt.Sprintf("%.0f%%", t*100))
+	}
+	b.WriteString(tui.StyleSubtle.Render("Tries to see it with each chance") + "\n")
+	b.WriteString(tui.StyleSubtle.Render(header) + "\n")
+	for _, row := range []struct {
+		label string
+		prob  float64
+	}{
+		{"Transfigure base gem", l.SpecificProb},
+		{fmt.Sprintf("%s pool roll", l.Color.Label()), l.PoolProb},
+	} {
+		line := fmt.Sprintf("%-22s ", row.label) + tui.StyleProb.Render(fmt.Sprintf("%8s", domain.FormatPct(row.prob)))
+		for _, t := range domain.LookupTargets {
+			line += fmt.Sprintf(" %6d", domain.AttemptsFor(row.prob, t))
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + tui.StyleSubtle.Render(fmt.Sprintf(
+		"1 of %d variants; a pool roll offers %d of %d %s gems",
+		l.Variants, domain.FontDraws, l.PoolSize, strings.ToLower(l.Color.Label()))) + "\n\n")
+	return b.String()
+}
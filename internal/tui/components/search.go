@@ -1,24 +1,47 @@
 package components
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
 
 	"github.com/ovestokke/gemcheck-tui/internal/domain"
 	"github.com/ovestokke/gemcheck-tui/internal/tui"
 )
 
-const maxSearchResults = 10
+// searchRows is how many results are shown at once; the rest scroll.
+const searchRows = 10
 
-// SearchModel is a fuzzy search overlay for gem entries.
+// searchWidth is the popup's width, border excluded.
+const searchWidth = 46
+
+// searchTarget is a searchable name: a base gem, or one of its variants.
+type searchTarget struct {
+	name    string
+	entry   int // index into allGems
+	variant int // index into the entry's Variants, -1 for the base gem
+}
+
+// searchTargets adapts the targets to fuzzy.Source.
+type searchTargets []searchTarget
+
+func (t searchTargets) String(i int) string { return t[i].name }
+func (t searchTargets) Len() int            { return len(t) }
+
+// SearchModel is a fuzzy search overlay over base gem and transfigured
+// variant names.
 type SearchModel struct {
 	input   textinput.Model
 	allGems []domain.GemEntry
-	results []domain.GemEntry
+	targets searchTargets
+	results fuzzy.Matches
 	cursor  int
+	offset  int // first result shown
 	active  bool
 	width   int
 	height  int
@@ -37,9 +60,21 @@ func NewSearch() SearchModel {
 	return SearchModel{input: ti}
 }
 
-func (m *SearchModel) SetGems(gems []domain.GemEntry) { m.allGems = gems }
-func (m *SearchModel) SetSize(w, h int)               { m.width = w; m.height = h }
-func (m SearchModel) Active() bool                     { return m.active }
+// SetGems sets the gems to search, by base name and variant names.
+func (m *SearchModel) SetGems(gems []domain.GemEntry) {
+	m.allGems = gems
+	m.targets = nil
+	for i, g := range gems {
+		m.targets = append(m.targets, searchTarget{name: g.BaseName, entry: i, variant: -1})
+		for j, v := range g.Variants {
+			m.targets = append(m.targets, searchTarget{name: v.Name, entry: i, variant: j})
+		}
+	}
+	m.filterResults()
+}
+
+func (m *SearchModel) SetSize(w, h int) { m.width = w; m.height = h }
+func (m SearchModel) Active() bool      { return m.active }
 
 // Open activates the search overlay.
 func (m *SearchModel) Open() tea.Cmd {
@@ -47,6 +82,7 @@ func (m *SearchModel) Open() tea.Cmd {
 	m.input.SetValue("")
 	m.results = nil
 	m.cursor = 0
+	m.offset = 0
 	m.input.Focus()
 	return textinput.Blink
 }
@@ -57,14 +93,27 @@ func (m *SearchModel) Close() {
 	m.input.Blur()
 }
 
-// SelectedEntry returns the entry under the cursor, if any.
+// SelectedEntry returns the gem of the result under the cursor, if any. A
+// variant result selects its base gem.
 func (m SearchModel) SelectedEntry() *domain.GemEntry {
 	if m.cursor >= 0 && m.cursor < len(m.results) {
-		return &m.results[m.cursor]
+		return &m.allGems[m.targets[m.results[m.cursor].Index].entry]
 	}
 	return nil
 }
 
+// SelectedVariant returns the name of the transfigured gem under the
+// cursor, or "" if the cursor is on a base gem.
+func (m SearchModel) SelectedVariant() string {
+	if m.cursor >= 0 && m.cursor < len(m.results) {
+		t := m.targets[m.results[m.cursor].Index]
+		if t.variant >= 0 {
+			return t.name
+		}
+	}
+	return ""
+}
+
 func (m SearchModel) Init() tea.Cmd {
 	return nil
 }
@@ -74,6 +123,7 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 		return m, nil
 	}
 
+	// Letters are all query text, so only non-letter keys move the cursor
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -82,45 +132,70 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 			return m, nil
 		case "enter":
 			return m, nil // caller checks SelectedEntry
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
+		case "up", "ctrl+p":
+			m.move(-1)
 			return m, nil
-		case "down", "j":
-			if m.cursor < len(m.results)-1 {
-				m.cursor++
-			}
+		case "down", "ctrl+n":
+			m.move(1)
+			return m, nil
+		case "pgup":
+			m.move(-searchRows)
+			return m, nil
+		case "pgdown":
+			m.move(searchRows)
 			return m, nil
 		}
 	}
 
+	// Only a changed query re-filters: cursor blinks come through here too
+	query := m.input.Value()
 	var cmd tea.Cmd
 	m.input, cmd = m.input.Update(msg)
-	m.filterResults()
+	if m.input.Value() != query {
+		m.filterResults()
+	}
 	return m, cmd
 }
 
-func (m *SearchModel) filterResults() {
-	query := strings.ToLower(m.input.Value())
-	if query == "" {
-		m.results = nil
-		m.cursor = 0
-		return
+// move moves the cursor by n results, scrolling to keep it in view.
+func (m *SearchModel) move(n int) {
+	m.cursor = max(min(m.cursor+n, len(m.results)-1), 0)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
 	}
+	if m.cursor >= m.offset+searchRows {
+		m.offset = m.cursor - searchRows + 1
+	}
+}
 
+// filterResults ranks the names matching the query, best match first, and
+// keeps the cursor within them.
+func (m *SearchModel) filterResults() {
 	m.results = nil
-	for _, g := range m.allGems {
-		if strings.Contains(strings.ToLower(g.BaseName), query) {
-			m.results = append(m.results, g)
-			if len(m.results) >= maxSearchResults {
-				break
-			}
-		}
+	if query := strings.TrimSpace(m.input.Value()); query != "" {
+		m.results = fuzzy.FindFrom(query, m.targets)
 	}
-	if m.cursor >= len(m.results) {
-		m.cursor = max(0, len(m.results)-1)
+	m.move(0)
+}
+
+// highlight renders name with the matched bytes picked out, cut to width
+// columns.
+func highlight(name string, matched []int, width int, base lipgloss.Style) string {
+	hit := base.Bold(true).Foreground(tui.ColorYellow)
+	var b strings.Builder
+	n := 0
+	for i, r := range name {
+		if n++; n == width && len(name) > i+len(string(r)) {
+			b.WriteString(base.Render("…"))
+			break
+		}
+		if slices.Contains(matched, i) {
+			b.WriteString(hit.Render(string(r)))
+		} else {
+			b.WriteString(base.Render(string(r)))
+		}
 	}
+	return b.String()
 }
 
 func (m SearchModel) View() string {
@@ -134,30 +209,48 @@ func (m SearchModel) View() string {
 
 	accentCursor := lipgloss.NewStyle().Foreground(tui.ColorLavender)
 
-	for i, g := range m.results {
+	end := min(m.offset+searchRows, len(m.results))
+	for i := m.offset; i < end; i++ {
+		match := m.results[i]
+		t := m.targets[match.Index]
+		g := m.allGems[t.entry]
+
 		gemColor := tui.ColorForGem(string(g.Color))
-		dot := lipgloss.NewStyle().Foreground(gemColor).Render("\u25cf ")
+		dot := lipgloss.NewStyle().Foreground(gemColor).Render("● ")
 
 		var prefix string
 		if i == m.cursor {
-			prefix = accentCursor.Render("\u276f ")
+			prefix = accentCursor.Render("❯ ")
 		} else {
 			prefix = "  "
 		}
 
-		evStyle := tui.PriceStyle(g.EV)
-		line := prefix + dot + g.BaseName + "  " +
-			evStyle.Render(domain.FormatChaos(g.EV)+" EV")
+		nameStyle := lipgloss.NewStyle().Foreground(tui.ColorText)
+		price := tui.PriceStyle(g.EV).Render(domain.FormatChaos(g.EV) + " EV")
+		if t.variant >= 0 {
+			v := g.Variants[t.variant]
+			nameStyle = lipgloss.NewStyle().Foreground(tui.ColorSubtext0)
+			price = tui.PriceStyle(v.SellPrice).Render(domain.FormatChaos(v.SellPrice))
+			if v.Excluded {
+				price = tui.StyleSubtle.Render("excluded")
+			}
+		}
+
+		room := searchWidth - 2 - lipgloss.Width(prefix+dot) - lipgloss.Width(price) - 2
+		line := prefix + dot + highlight(t.name, match.MatchedIndexes, room, nameStyle) + "  " + price
 		b.WriteString(line + "\n")
 	}
 
-	if m.input.Value() == "" && len(m.results) == 0 {
+	switch {
+	case m.input.Value() == "" && len(m.results) == 0:
 		b.WriteString(tui.StyleSubtle.Render("  Type to search..."))
-	} else if m.input.Value() != "" && len(m.results) == 0 {
+	case m.input.Value() != "" && len(m.results) == 0:
 		b.WriteString(tui.StyleSubtle.Render("  No results"))
+	case len(m.results) > searchRows:
+		b.WriteString(tui.StyleSubtle.Render(fmt.Sprintf("  %d-%d of %d", m.offset+1, end, len(m.results))))
 	}
 
-	popup := tui.StyleSearchInput.Width(46).Render(b.String())
+	popup := tui.StyleSearchInput.Width(searchWidth).Render(b.String())
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
 }
@@ -0,0 +1,41 @@
+package components
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+)
+
+func TestSearchKeepsCursor(t *testing.T) {
+	var gems []domain.GemEntry
+	for i := range 30 {
+		gems = append(gems, domain.GemEntry{BaseName: fmt.Sprintf("Gem %02d", i), Color: domain.Red})
+	}
+	m := NewSearch()
+	m.SetGems(gems)
+	m.Open()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("gem")})
+	for range 15 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	want := m.SelectedEntry()
+
+	// Messages that don't change the query, such as cursor blinks, leave the
+	// cursor and scroll alone
+	m, _ = m.Update(struct{}{})
+	if got := m.SelectedEntry(); got == nil || got.BaseName != want.BaseName {
+		t.Errorf("cursor moved from %s to %v", want.BaseName, got)
+	}
+	if m.offset == 0 {
+		t.Error("scroll reset to the top")
+	}
+
+	// A narrower query keeps the cursor on a result
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" 0")})
+	if m.cursor >= len(m.results) || m.offset > m.cursor {
+		t.Errorf("cursor %d, offset %d with %d results", m.cursor, m.offset, len(m.results))
+	}
+}
@@ -0,0 +1,137 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// SensitivityModel ranks the gems in a color pool by their contribution to pool EV.
+type SensitivityModel struct {
+	color    domain.GemColor
+	poolEV   float64
+	contribs []domain.GemContribution
+	active   bool
+	scroll   int
+	width    int
+	height   int
+}
+
+// NewSensitivity creates a sensitivity popup.
+func NewSensitivity() SensitivityModel {
+	return SensitivityModel{}
+}
+
+func (m *SensitivityModel) SetSize(w, h int) { m.width = w; m.height = h }
+func (m SensitivityModel) Active() bool      { return m.active }
+
+// Show displays the contribution ranking for a color pool.
+func (m *SensitivityModel) Show(stats domain.ColorStats, contribs []domain.GemContribution) {
+	m.color = stats.Color
+	m.poolEV = stats.PoolEV
+	m.contribs = contribs
+	m.active = true
+	m.scroll = 0
+}
+
+// Hide closes the sensitivity popup.
+func (m *SensitivityModel) Hide() {
+	m.active = false
+	m.contribs = nil
+}
+
+func (m SensitivityModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m SensitivityModel) Update(msg tea.Msg) (SensitivityModel, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "d":
+			m.Hide()
+		case "up", "k":
+			if m.scroll > 0 {
+				m.scroll--
+			}
+		case "down", "j":
+			if m.scroll < len(m.contribs)-1 {
+				m.scroll++
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m SensitivityModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	popupWidth := min(80, m.width-4)
+	innerWidth := popupWidth - 6
+
+	var b strings.Builder
+
+	gemColor := tui.ColorForGem(string(m.color))
+	title := lipgloss.NewStyle().Bold(true).Foreground(gemColor).
+		Render(m.color.Label() + " pool EV drivers")
+	b.WriteString(title + "\n")
+	b.WriteString(tui.StyleHeaderDivider.Render(strings.Repeat("─", innerWidth)) + "\n")
+	b.WriteString(fmt.Sprintf("Pool EV: %s  %s  %d gems\n\n",
+		tui.PriceStyle(m.poolEV).Render(domain.FormatChaos(m.poolEV)),
+		tui.Separator,
+		len(m.contribs)))
+
+	header := fmt.Sprintf("%-3s %-26s %8s %7s %8s %6s %8s",
+		"#", "Gem", "Price", "P(best)", "Contrib", "Share", "-50%")
+	b.WriteString(tui.StyleSubtle.Render(header) + "\n")
+
+	maxVisible := max(5, m.height-14)
+	end := min(len(m.contribs), m.scroll+maxVisible)
+	for i := m.scroll; i < end; i++ {
+		c := m.contribs[i]
+		name := c.Name
+		if len(name) > 26 {
+			name = name[:25] + "…"
+		}
+		price := fmt.Sprintf("%8s", domain.FormatChaos(c.SellPrice))
+		contrib := fmt.Sprintf("%8s", domain.FormatChaos(c.Contribution))
+		drop := fmt.Sprintf("%8s", domain.FormatChaos(0))
+		if c.DropDelta < 0 {
+			drop = fmt.Sprintf("%8s", "-"+domain.FormatChaos(-c.DropDelta))
+		}
+
+		shareStyle := tui.StyleSubtle
+		if c.Share >= 0.25 {
+			shareStyle = tui.StyleError
+		} else if c.Share >= 0.10 {
+			shareStyle = tui.StylePriceMid
+		}
+
+		b.WriteString(fmt.Sprintf("%-3d %-26s %s %7s %s %s %s\n",
+			i+1,
+			name,
+			tui.PriceStyle(c.SellPrice).Render(price),
+			tui.StyleProb.Render(fmt.Sprintf("%7s", domain.FormatPct(c.PBest))),
+			tui.PriceStyle(c.Contribution).Render(contrib),
+			shareStyle.Render(fmt.Sprintf("%6s", domain.FormatPct(c.Share))),
+			tui.StyleSubtle.Render(drop)))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(tui.StyleHelp.Render(fmt.Sprintf("esc close  ↑↓ scroll  %d/%d",
+		min(m.scroll+1, len(m.contribs)), len(m.contribs))))
+
+	popup := tui.StyleDetailPopup.Width(popupWidth).Render(b.String())
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
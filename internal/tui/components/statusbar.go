@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/ovestokke/gemcheck-tui/internal/tui"
@@ -15,6 +16,8 @@ type StatusBarModel struct {
 	league   string
 	cacheAge time.Duration
 	gemCount int
+	message  string
+	isErr    bool
 	width    int
 }
 
@@ -28,6 +31,14 @@ func (m *StatusBarModel) SetCacheAge(d time.Duration) { m.cacheAge = d }
 func (m *StatusBarModel) SetGemCount(n int)        { m.gemCount = n }
 func (m *StatusBarModel) SetWidth(w int)           { m.width = w }
 
+// SetMessage shows msg in place of the key help until ClearMessage.
+func (m *StatusBarModel) SetMessage(msg string, isErr bool) {
+	m.message = msg
+	m.isErr = isErr
+}
+
+func (m *StatusBarModel) ClearMessage() { m.message = "" }
+
 func (m StatusBarModel) View() string {
 	// Segment 1: League pill
 	leagueSeg := tui.StyleStatusLeague.Render(m.league)
@@ -39,11 +50,25 @@ func (m StatusBarModel) View() string {
 	}
 	infoSeg := tui.StyleStatusInfo.Render(infoText)
 
-	// Segment 3: Help keys (right-aligned)
-	helpSeg := tui.StyleStatusHelp.Render("1-3 tab  / search  r refresh  q quit")
-
 	// Calculate gap fill
 	leftWidth := lipgloss.Width(leagueSeg) + lipgloss.Width(infoSeg)
+
+	// Segment 3: Help keys (right-aligned)
+	helpSeg := tui.StyleStatusHelp.Render(helpText(m.width - leftWidth - 3))
+
+	// A message replaces the help, keeping its tail (the file name) visible
+	if m.message != "" {
+		style := tui.StyleStatusMessage
+		if m.isErr {
+			style = tui.StyleStatusError
+		}
+		msg := m.message
+		if room := m.width - leftWidth - 3; room > 0 && len([]rune(msg)) > room {
+			r := []rune(msg)
+			msg = "…" + string(r[len(r)-room+1:])
+		}
+		helpSeg = style.Render(msg)
+	}
 	rightWidth := lipgloss.Width(helpSeg)
 	gap := m.width - leftWidth - rightWidth
 	if gap < 1 {
@@ -55,6 +80,29 @@ func (m StatusBarModel) View() string {
 	return lipgloss.NewStyle().Width(m.width).Render(content)
 }
 
+// helpText lists the main keys as currently bound, leaving out the least
+// used ones that don't fit in room columns. Quit is always shown.
+func helpText(room int) string {
+	k := tui.Keys
+	parts := []string{k.Tab1.Help().Key + "-" + k.Tab4.Help().Key + " tab"}
+	for _, b := range []struct {
+		binding key.Binding
+		label   string
+	}{
+		{k.Search, "search"}, {k.Rank, "rank"}, {k.Sort, "sort"}, {k.Filter, "filter"}, {k.Watch, "watch"}, {k.Refresh, "refresh"}, {k.Changes, "changes"},
+		{k.Drivers, "drivers"}, {k.Export, "export"}, {k.League, "league"}, {k.Compare, "compare"},
+	} {
+		parts = append(parts, b.binding.Help().Key+" "+b.label)
+	}
+	quit := k.Quit.Help().Key + " quit"
+
+	n := len(parts)
+	for n > 0 && lipgloss.Width(strings.Join(append(parts[:n:n], quit), "  ")) > room {
+		n--
+	}
+	return strings.Join(append(parts[:n:n], quit), "  ")
+}
+
 func formatAge(d time.Duration) string {
 	if d <= 0 {
 		return "fresh"
@@ -0,0 +1,129 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/history"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+)
+
+// TrendModel charts pool EV per color, and one gem's EV, from recorded history.
+type TrendModel struct {
+	league     string
+	entryName  string
+	entryColor domain.GemColor
+	pools      map[domain.GemColor][]history.Point
+	entry      []history.Point
+	loading    bool
+	err        error
+	spinner    SpinnerModel
+	width      int
+	height     int
+}
+
+// NewTrend creates a trend screen.
+func NewTrend() TrendModel {
+	return TrendModel{}
+}
+
+func (m *TrendModel) SetSize(w, h int) { m.width = w; m.height = h }
+
+// Open resets the screen for a league and, optionally, a gem entry while its
+// history loads.
+func (m *TrendModel) Open(league string, entry *domain.GemEntry) tea.Cmd {
+	m.league = league
+	m.entryName = ""
+	if entry != nil {
+		m.entryName = entry.BaseName
+		m.entryColor = entry.Color
+	}
+	m.pools = nil
+	m.entry = nil
+	m.err = nil
+	m.loading = true
+	m.spinner = NewSpinner("Replaying price history...")
+	return m.spinner.Init()
+}
+
+// SetData fills the charts from a finished history replay.
+func (m *TrendModel) SetData(msg tui.TrendLoadedMsg) {
+	m.loading = false
+	m.err = msg.Err
+	m.pools = msg.Pools
+	m.entry = msg.Entry
+}
+
+func (m TrendModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TrendModel) Update(msg tea.Msg) (TrendModel, tea.Cmd) {
+	if !m.loading {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+func (m TrendModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.StyleTitle.Render("Pool EV over time") + "  " +
+		tui.StyleSubtle.Render(m.league) + "\n")
+	b.WriteString(tui.StyleHeaderDivider.Render(strings.Repeat("─", m.width)) + "\n")
+
+	footer := tui.StyleHelp.Render("esc back")
+
+	switch {
+	case m.loading:
+		body := lipgloss.Place(m.width, m.height-3, lipgloss.Center, lipgloss.Center, m.spinner.View())
+		return b.String() + body + "\n" + footer
+	case m.err != nil:
+		b.WriteString(tui.StyleError.Render("Error: "+m.err.Error()) + "\n")
+		return b.String() + footer
+	case len(m.pools[domain.Red]) == 0:
+		b.WriteString(tui.StyleSubtle.Render("No history recorded for this league yet. Prices are recorded on each fetch; press r to refresh.") + "\n")
+		return b.String() + footer
+	}
+
+	var pools []chartSeries
+	for _, c := range domain.AllColors {
+		pools = append(pools, chartSeries{
+			Label:  c.Label(),
+			Color:  tui.ColorForGem(string(c)),
+			Points: m.pools[c],
+		})
+	}
+
+	// Split the height between the pool chart and the gem chart
+	avail := m.height - 7
+	poolH := avail
+	if m.entryName != "" {
+		poolH = avail * 3 / 5
+	}
+
+	b.WriteString(renderLegend(pools) + "  " +
+		tui.StyleSubtle.Render(fmt.Sprintf("(%d snapshots)", len(m.pools[domain.Red]))) + "\n")
+	b.WriteString(renderChart(pools, m.width, max(poolH, 4)) + "\n")
+
+	if m.entryName != "" {
+		entry := []chartSeries{{
+			Label:  m.entryName + " EV",
+			Color:  tui.ColorForGem(string(m.entryColor)),
+			Points: m.entry,
+		}}
+		b.WriteString("\n" + renderLegend(entry) + "\n")
+		if len(m.entry) == 0 {
+			b.WriteString(tui.StyleSubtle.Render("No history for this gem") + "\n")
+		} else {
+			b.WriteString(renderChart(entry, m.width, max(avail-poolH-2, 4)) + "\n")
+		}
+	}
+
+	return b.String() + footer
+}
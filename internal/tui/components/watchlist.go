@@ -0,0 +1,210 @@
+package components
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/tui"
+	"github.com/ovestokke/gemcheck-tui/internal/watchlist"
+)
+
+// watchRow is a watched gem with its current worth, if it is still priced.
+type watchRow struct {
+	item  watchlist.Item
+	base  string // the base gem, for a transfigured gem
+	color domain.GemColor
+	now   float64
+	found bool
+}
+
+func (r watchRow) FilterValue() string { return r.item.Name }
+
+// Widths of the watchlist's fixed columns.
+const (
+	watchValueWidth  = 9
+	watchChangeWidth = 16
+	watchAgeWidth    = 6
+)
+
+// watchDelegate renders watched gems as table rows with left-border
+// selection.
+type watchDelegate struct {
+	now func() time.Time
+}
+
+func (d watchDelegate) Height() int                             { return 1 }
+func (d watchDelegate) Spacing() int                            { return 0 }
+func (d watchDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d watchDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	row, ok := listItem.(watchRow)
+	if !ok {
+		return
+	}
+	selected := index == m.Index()
+
+	gemColor := tui.ColorForGem(string(row.color))
+	border := "  "
+	nameStyle := lipgloss.NewStyle().Foreground(tui.ColorSubtext0)
+	if selected {
+		border = lipgloss.NewStyle().Foreground(gemColor).Render("┃ ")
+		nameStyle = nameStyle.Bold(true).Foreground(tui.ColorText)
+	}
+	dot := lipgloss.NewStyle().Foreground(gemColor).Render("● ")
+	if !row.found {
+		dot = tui.StyleSubtle.Render("○ ")
+	}
+
+	nameWidth := max(m.Width()-4-watchValueWidth*2-watchChangeWidth-watchAgeWidth-4*columnGap, 10)
+	name := row.item.Name
+	if row.item.Variant {
+		name = "↳ " + name
+	}
+	gap := strings.Repeat(" ", columnGap)
+
+	var b strings.Builder
+	b.WriteString(border + dot)
+	b.WriteString(nameStyle.Render(fmt.Sprintf("%-*s", nameWidth, truncate(name, nameWidth))))
+	b.WriteString(gap + padLeft(watchValue(row.now, row.found), watchValueWidth))
+	b.WriteString(gap + padLeft(tui.StyleSubtle.Render(domain.FormatChaos(row.item.Value)), watchValueWidth))
+	b.WriteString(gap + padLeft(watchChange(row), watchChangeWidth))
+	b.WriteString(gap + padLeft(tui.StyleSubtle.Render(formatSince(d.now().Sub(row.item.Added))), watchAgeWidth))
+	fmt.Fprint(w, b.String())
+}
+
+func watchValue(v float64, found bool) string {
+	if !found {
+		return tui.StyleSubtle.Render("gone")
+	}
+	return tui.PriceStyle(v).Render(domain.FormatChaos(v))
+}
+
+// watchChange renders the change in worth since the gem was watched, with
+// the percentage when there was a worth to compare with.
+func watchChange(r watchRow) string {
+	if !r.found {
+		return ""
+	}
+	d := r.now - r.item.Value
+	text := domain.FormatChaosSigned(d)
+	if r.item.Value > 0 && d != 0 {
+		text += fmt.Sprintf(" %+.0f%%", d/r.item.Value*100)
+	}
+	switch {
+	case d > 0:
+		return tui.StyleProb.Render("▲ " + text)
+	case d < 0:
+		return tui.StyleError.Render("▼ " + text)
+	}
+	return tui.StyleSubtle.Render(text)
+}
+
+// formatSince renders how long ago something happened in its largest unit.
+func formatSince(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// WatchlistModel is the Watchlist tab: watched base gems and transfigured
+// gems across all colors, with their worth now and when they were watched.
+type WatchlistModel struct {
+	list   list.Model
+	count  int
+	width  int
+	height int
+}
+
+// NewWatchlist creates an empty Watchlist tab.
+func NewWatchlist(width, height int) WatchlistModel {
+	l := list.New(nil, watchDelegate{now: time.Now}, width, height-1)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.DisableQuitKeybindings()
+	l.KeyMap.CursorUp = key.NewBinding(key.WithKeys("up", "k"))
+	l.KeyMap.CursorDown = key.NewBinding(key.WithKeys("down", "j"))
+	l.SetStatusBarItemName("watched gem", "watched gems")
+
+	return WatchlistModel{list: l, width: width, height: height}
+}
+
+// SetItems lists the watched gems in order, priced from r.
+func (m *WatchlistModel) SetItems(items []watchlist.Item, r domain.ProcessedResult) {
+	rows := make([]list.Item, len(items))
+	for i, it := range items {
+		row := watchRow{item: it}
+		if it.Variant {
+			if l, ok := domain.LookupVariant(r, it.Name); ok {
+				row.base, row.color = l.BaseName, l.Color
+				row.now, row.found = l.Variant.SellPrice, true
+			}
+		} else if e, ok := r.Entry(it.Name); ok {
+			row.base, row.color = e.BaseName, e.Color
+			row.now, row.found = e.EV, true
+		}
+		rows[i] = row
+	}
+	m.count = len(rows)
+	m.list.SetItems(rows)
+	if m.list.Index() >= len(rows) {
+		m.list.Select(max(len(rows)-1, 0))
+	}
+}
+
+// Len returns the number of watched gems.
+func (m WatchlistModel) Len() int { return m.count }
+
+// Selected returns the watched gem under the cursor and its base gem, empty
+// if it is no longer priced.
+func (m WatchlistModel) Selected() (watchlist.Item, string, bool) {
+	row, ok := m.list.SelectedItem().(watchRow)
+	return row.item, row.base, ok
+}
+
+// SetSize updates the tab dimensions, including the header row.
+func (m *WatchlistModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.list.SetSize(width, height-1)
+}
+
+func (m WatchlistModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m WatchlistModel) Update(msg tea.Msg) (WatchlistModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m WatchlistModel) View() string {
+	if m.count == 0 {
+		hint := fmt.Sprintf("Nothing watched yet. Press %s on a gem, or on a variant in its detail, to watch it.",
+			tui.Keys.Watch.Help().Key)
+		return "\n  " + tui.StyleSubtle.Render(hint)
+	}
+
+	nameWidth := max(m.width-4-watchValueWidth*2-watchChangeWidth-watchAgeWidth-4*columnGap, 10)
+	gap := strings.Repeat(" ", columnGap)
+	header := "    " + fmt.Sprintf("%-*s", nameWidth, "Gem") +
+		gap + fmt.Sprintf("%*s", watchValueWidth, "Now") +
+		gap + fmt.Sprintf("%*s", watchValueWidth, "Added at") +
+		gap + fmt.Sprintf("%*s", watchChangeWidth, "Change") +
+		gap + fmt.Sprintf("%*s", watchAgeWidth, "Since")
+	return tui.StyleSubtle.Render(header) + "\n" + m.list.View()
+}
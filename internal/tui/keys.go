@@ -1,64 +1,217 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
-	Tab1    key.Binding
-	Tab2    key.Binding
-	Tab3    key.Binding
-	NextTab key.Binding
-	Search  key.Binding
-	Refresh key.Binding
-	Select  key.Binding
-	Back    key.Binding
-	Up      key.Binding
-	Down    key.Binding
-	Quit    key.Binding
+	Tab1        key.Binding
+	Tab2        key.Binding
+	Tab3        key.Binding
+	Tab4        key.Binding
+	NextTab     key.Binding
+	Search      key.Binding
+	Refresh     key.Binding
+	Select      key.Binding
+	Drivers     key.Binding
+	Rank        key.Binding
+	Sort        key.Binding
+	SortReverse key.Binding
+	Filter      key.Binding
+	Watch       key.Binding
+	Trend       key.Binding
+	Changes     key.Binding
+	Export      key.Binding
+	Reload      key.Binding
+	League      key.Binding
+	Compare     key.Binding
+	Back        key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Quit        key.Binding
+}
+
+// Keys are the active bindings: the defaults with any config overrides.
+var Keys = DefaultKeys()
+
+// DefaultKeys returns the built-in bindings.
+func DefaultKeys() KeyMap {
+	return KeyMap{
+		Tab1: key.NewBinding(
+			key.WithKeys("1"),
+			key.WithHelp("1", "red"),
+		),
+		Tab2: key.NewBinding(
+			key.WithKeys("2"),
+			key.WithHelp("2", "green"),
+		),
+		Tab3: key.NewBinding(
+			key.WithKeys("3"),
+			key.WithHelp("3", "blue"),
+		),
+		Tab4: key.NewBinding(
+			key.WithKeys("4"),
+			key.WithHelp("4", "watchlist"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next tab"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Drivers: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "pool EV drivers"),
+		),
+		Rank: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "ranking"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort column"),
+		),
+		SortReverse: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "reverse sort"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "filter"),
+		),
+		Watch: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "watch"),
+		),
+		Trend: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "EV trend"),
+		),
+		Changes: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "changes"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "export tab"),
+		),
+		Reload: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reload config"),
+		),
+		League: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "leagues"),
+		),
+		Compare: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "compare leagues"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// actions maps config action names to the rebindable bindings. Navigation
+// keys belong to the list component and aren't included.
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"tab1":         &k.Tab1,
+		"tab2":         &k.Tab2,
+		"tab3":         &k.Tab3,
+		"tab4":         &k.Tab4,
+		"next_tab":     &k.NextTab,
+		"search":       &k.Search,
+		"refresh":      &k.Refresh,
+		"select":       &k.Select,
+		"drivers":      &k.Drivers,
+		"rank":         &k.Rank,
+		"sort":         &k.Sort,
+		"sort_reverse": &k.SortReverse,
+		"filter":       &k.Filter,
+		"watch":        &k.Watch,
+		"trend":        &k.Trend,
+		"changes":      &k.Changes,
+		"export":       &k.Export,
+		"reload":       &k.Reload,
+		"league":       &k.League,
+		"compare":      &k.Compare,
+		"back":         &k.Back,
+		"quit":         &k.Quit,
+	}
+}
+
+// ApplyKeys rebinds actions on top of DefaultKeys and makes the result the
+// active Keys. Unknown actions, empty key lists and keys bound to two
+// main-screen actions are errors, and leave Keys unchanged.
+func ApplyKeys(overrides map[string][]string) error {
+	km := DefaultKeys()
+	actions := km.actions()
+
+	for _, name := range sortedKeys(overrides) {
+		b, ok := actions[name]
+		if !ok {
+			return fmt.Errorf("keys: unknown action %q", name)
+		}
+		keys := overrides[name]
+		if len(keys) == 0 {
+			return fmt.Errorf("keys.%s: no keys given", name)
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+
+	// Back only applies inside overlays, so it may share a key
+	owner := make(map[string]string)
+	for _, name := range sortedKeys(actions) {
+		if name == "back" {
+			continue
+		}
+		for _, k := range actions[name].Keys() {
+			if other, ok := owner[k]; ok {
+				return fmt.Errorf("keys: %q is bound to both %s and %s", k, other, name)
+			}
+			owner[k] = name
+		}
+	}
+
+	Keys = km
+	return nil
 }
 
-var Keys = KeyMap{
-	Tab1: key.NewBinding(
-		key.WithKeys("1"),
-		key.WithHelp("1", "red"),
-	),
-	Tab2: key.NewBinding(
-		key.WithKeys("2"),
-		key.WithHelp("2", "green"),
-	),
-	Tab3: key.NewBinding(
-		key.WithKeys("3"),
-		key.WithHelp("3", "blue"),
-	),
-	NextTab: key.NewBinding(
-		key.WithKeys("tab"),
-		key.WithHelp("tab", "next tab"),
-	),
-	Search: key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "search"),
-	),
-	Refresh: key.NewBinding(
-		key.WithKeys("r"),
-		key.WithHelp("r", "refresh"),
-	),
-	Select: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "back"),
-	),
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
+func sortedKeys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyKeys(t *testing.T) {
+	t.Cleanup(func() { Keys = DefaultKeys() })
+
+	if err := ApplyKeys(map[string][]string{"refresh": {"f5", "ctrl+r"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := Keys.Refresh.Keys(); len(got) != 2 || got[0] != "f5" {
+		t.Errorf("refresh keys = %v", got)
+	}
+	if got := Keys.Refresh.Help().Key; got != "f5/ctrl+r" {
+		t.Errorf("refresh help = %q", got)
+	}
+
+	for overrides, want := range map[string]map[string][]string{
+		"unknown action": {"fly": {"f"}},
+		"no keys given":  {"quit": {}},
+		"bound to both":  {"refresh": {"o"}},
+	} {
+		err := ApplyKeys(want)
+		if err == nil || !strings.Contains(err.Error(), overrides) {
+			t.Errorf("expected %q error, got %v", overrides, err)
+		}
+	}
+	if got := Keys.Refresh.Keys()[0]; got != "f5" {
+		t.Errorf("failed ApplyKeys changed Keys: refresh = %q", got)
+	}
+}
@@ -1,6 +1,10 @@
 package tui
 
-import "github.com/ovestokke/gemcheck-tui/internal/domain"
+import (
+	"github.com/ovestokke/gemcheck-tui/internal/domain"
+	"github.com/ovestokke/gemcheck-tui/internal/history"
+	"github.com/ovestokke/gemcheck-tui/internal/watchlist"
+)
 
 // Messages for async operations
 
@@ -9,8 +13,11 @@ type LeaguesFetchedMsg struct {
 	Err     error
 }
 
+// LeagueSelectedMsg picks a league to switch to or, with Compare, to
+// compare the current league with.
 type LeagueSelectedMsg struct {
-	League domain.League
+	League  domain.League
+	Compare bool
 }
 
 type WikiFetchedMsg struct {
@@ -19,14 +26,76 @@ type WikiFetchedMsg struct {
 }
 
 type PricesFetchedMsg struct {
+	League string
 	Prices []domain.GemPrice
 	Err    error
 }
 
 type DataReadyMsg struct {
+	League string
+	Prices []domain.GemPrice
 	Result domain.ProcessedResult
 }
 
+// TrendLoadedMsg carries pool EV per color, and optionally one gem's EV,
+// replayed from price history for the trend opened as Gen.
+type TrendLoadedMsg struct {
+	Gen   int
+	Pools map[domain.GemColor][]history.Point
+	Entry []history.Point
+	Err   error
+}
+
+// SnapshotsLoadedMsg carries League's saved price snapshots, oldest first,
+// for the changes overlay opened as Gen.
+type SnapshotsLoadedMsg struct {
+	League    string
+	Gen       int
+	Snapshots []history.Snapshot
+	Err       error
+}
+
+// DeltasExpiredMsg clears the change markers set by refresh Gen.
+type DeltasExpiredMsg struct {
+	Gen int
+}
+
+// ExportedMsg reports a table export written to Path.
+type ExportedMsg struct {
+	Path string
+	Err  error
+}
+
+// SaveFilterMsg asks for Filter to be saved to the config file as a preset.
+type SaveFilterMsg struct {
+	Name   string
+	Filter domain.GemFilter
+}
+
+// FilterSavedMsg reports a SaveFilterMsg done.
+type FilterSavedMsg struct {
+	Name   string
+	Filter domain.GemFilter
+	Err    error
+}
+
+// WatchlistLoadedMsg delivers League's saved watchlist.
+type WatchlistLoadedMsg struct {
+	League string
+	List   watchlist.List
+	Err    error
+}
+
+// WatchlistSavedMsg reports the watchlist written after a change.
+type WatchlistSavedMsg struct {
+	Err error
+}
+
+// StatusClearMsg clears status bar message Gen.
+type StatusClearMsg struct {
+	Gen int
+}
+
 type ErrMsg struct {
 	Err error
 }
@@ -26,6 +26,9 @@ var (
 	ColorGold     = lipgloss.Color("#f5c211")
 )
 
+// WatchedMark prefixes the names of watched gems.
+const WatchedMark = "\u2605 "
+
 // Separator used between status items
 var Separator = lipgloss.NewStyle().Foreground(ColorOverlay0).Render(" \u2022 ")
 
@@ -51,6 +54,10 @@ var (
 	StyleHelp = lipgloss.NewStyle().
 			Foreground(ColorOverlay0)
 
+	// StyleWatched marks watched gems.
+	StyleWatched = lipgloss.NewStyle().
+			Foreground(ColorGold)
+
 	// --- Branded logo ---
 	StyleLogo = lipgloss.NewStyle().
 			Bold(true).
@@ -114,6 +121,16 @@ var (
 	StyleStatusBar = lipgloss.NewStyle().
 			Background(ColorMantle)
 
+	StyleStatusMessage = lipgloss.NewStyle().
+				Foreground(ColorGreen).
+				Background(ColorCrust).
+				Padding(0, 1)
+
+	StyleStatusError = lipgloss.NewStyle().
+				Foreground(ColorRed).
+				Background(ColorCrust).
+				Padding(0, 1)
+
 	// --- Left-border selection indicators ---
 	StyleSelectedBorder = lipgloss.NewStyle().
 				Foreground(ColorYellow).
@@ -134,12 +151,18 @@ var (
 			Foreground(ColorOverlay1)
 )
 
+// Price tier thresholds in chaos, set from the config.
+var (
+	PriceTierHigh = 50.0
+	PriceTierMid  = 10.0
+)
+
 // PriceStyle returns a tier-colored style based on chaos value.
 func PriceStyle(chaos float64) lipgloss.Style {
 	switch {
-	case chaos >= 50:
+	case chaos >= PriceTierHigh:
 		return StylePriceHigh
-	case chaos >= 10:
+	case chaos >= PriceTierMid:
 		return StylePriceMid
 	default:
 		return StylePriceLow
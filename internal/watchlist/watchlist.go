@@ -0,0 +1,144 @@
+// Package watchlist keeps the base gems and transfigured gems tracked in a
+// league, one JSON file per league under the config directory.
+package watchlist
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Item is a watched gem.
+type Item struct {
+	// Name is a base gem's name, or a transfigured gem's with Variant set.
+	Name    string `json:"name"`
+	Variant bool   `json:"variant,omitempty"`
+
+	// Added is when the gem was watched, and Value its worth then: a base
+	// gem's EV or a transfigured gem's price.
+	Added time.Time `json:"added"`
+	Value float64   `json:"value"`
+}
+
+// List is a league's watched gems, oldest first.
+type List struct {
+	Items []Item `json:"items"`
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Path returns the file for league's watchlist in dir.
+func Path(dir, league string) string {
+	return filepath.Join(dir, unsafeChars.ReplaceAllString(league, "_")+".json")
+}
+
+// Load reads the watchlist at path. A missing file yields an empty list.
+func Load(path string) (List, error) {
+	var l List
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, err
+	}
+	err = json.Unmarshal(b, &l)
+	return l, err
+}
+
+// Save writes l to path, creating its directory. The list is written to a
+// temporary file first, so path always holds a whole list.
+func Save(path string, l List) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Saver saves lists one at a time, in the order they were made: a list
+// older than the last one written to the same file is skipped, so quick
+// changes saved in the background can't leave a stale list behind.
+type Saver struct {
+	mu      sync.Mutex
+	version uint64
+	written map[string]uint64
+}
+
+// NewSaver creates a Saver.
+func NewSaver() *Saver {
+	return &Saver{written: make(map[string]uint64)}
+}
+
+// Version stamps a list about to be saved. Later stamps are newer.
+func (s *Saver) Version() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	return s.version
+}
+
+// Save writes l, stamped with version, to path unless a newer list has been
+// written there already.
+func (s *Saver) Save(path string, l List, version uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written[path] > version {
+		return nil
+	}
+	if err := Save(path, l); err != nil {
+		return err
+	}
+	s.written[path] = version
+	return nil
+}
+
+func (l List) index(name string) int {
+	return slices.IndexFunc(l.Items, func(it Item) bool { return strings.EqualFold(it.Name, name) })
+}
+
+// Has reports whether the gem called name is watched.
+func (l List) Has(name string) bool { return l.index(name) >= 0 }
+
+// Toggle watches it, or stops watching the gem with its name if it already
+// is. It reports whether it was added.
+func (l *List) Toggle(it Item) bool {
+	if i := l.index(it.Name); i >= 0 {
+		l.Items = slices.Delete(slices.Clone(l.Items), i, i+1)
+		return false
+	}
+	l.Items = append(slices.Clone(l.Items), it)
+	return true
+}
+
+// Names returns the watched names, for lookups by exact name.
+func (l List) Names() map[string]bool {
+	names := make(map[string]bool, len(l.Items))
+	for _, it := range l.Items {
+		names[it.Name] = true
+	}
+	return names
+}
@@ -0,0 +1,84 @@
+package watchlist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "watchlist")
+	path := Path(dir, "My League (PL123)")
+	if filepath.Base(path) != "My_League_PL123_.json" {
+		t.Errorf("unsafe characters kept: %s", path)
+	}
+
+	l, err := Load(path)
+	if err != nil || len(l.Items) != 0 {
+		t.Fatalf("missing file: %+v, %v", l, err)
+	}
+
+	added := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	want := List{Items: []Item{
+		{Name: "Boneshatter", Added: added, Value: 40},
+		{Name: "Boneshatter of Carnage", Variant: true, Added: added, Value: 100},
+	}}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip: got %+v, %v", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("corrupt file loaded without error")
+	}
+}
+
+func TestToggle(t *testing.T) {
+	var l List
+	if !l.Toggle(Item{Name: "Boneshatter", Value: 40}) || !l.Toggle(Item{Name: "Cleave of Rage", Variant: true}) {
+		t.Fatal("new items not added")
+	}
+	if !l.Has("boneshatter") || !l.Names()["Cleave of Rage"] {
+		t.Errorf("added items missing: %+v", l)
+	}
+
+	// A copy taken before toggling keeps its items
+	before := l
+	if l.Toggle(Item{Name: "BONESHATTER"}) {
+		t.Error("watched item added twice")
+	}
+	if l.Has("Boneshatter") || len(l.Items) != 1 {
+		t.Errorf("item not removed: %+v", l)
+	}
+	if len(before.Items) != 2 || before.Items[0].Name != "Boneshatter" {
+		t.Errorf("earlier copy changed: %+v", before)
+	}
+}
+
+func TestSaverSkipsStale(t *testing.T) {
+	path := Path(t.TempDir(), "Standard")
+	s := NewSaver()
+	older, newer := s.Version(), s.Version()
+	want := List{Items: []Item{{Name: "Boneshatter"}, {Name: "Cleave"}}}
+
+	// The newer list lands first, as a slow older save finishing late would
+	if err := s.Save(path, want, newer); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(path, List{Items: want.Items[:1]}, older); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("stale list written: got %+v, %v", got, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected only the list file, got %d entries", len(entries))
+	}
+}